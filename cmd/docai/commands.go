@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zjoart/docai/internal/upload"
+	"github.com/zjoart/docai/pkg/client"
+)
+
+var errLocalUnsupported = errors.New("not available with --local; documents only exist on the server")
+
+func runUpload(opts options, args []string) error {
+	fs := flag.NewFlagSet("upload", flag.ExitOnError)
+	analyze := fs.Bool("analyze", false, "queue each document for analysis after upload")
+	wait := fs.Bool("wait", false, "with --analyze, wait for analysis to finish")
+	waitTimeout := fs.Duration("wait-timeout", 10*time.Minute, "how long --wait waits in total")
+	concurrency := fs.Int("concurrency", 4, "files uploaded at once")
+	format := fs.String("format", "text", "output format: text, json or csv")
+	out := fs.String("output", "", "write results to this file instead of stdout")
+	fs.Parse(args)
+
+	paths, err := expandPaths(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	if opts.local {
+		local, err := newLocal(*analyze)
+		if err != nil {
+			return err
+		}
+		return report(*format, *out, each(context.Background(), paths, *concurrency, local.process))
+	}
+
+	c := newClient(opts)
+	ctx := context.Background()
+	if *wait {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *waitTimeout)
+		defer cancel()
+	}
+	results := each(ctx, paths, *concurrency, func(ctx context.Context, path string) result {
+		doc, err := c.UploadFile(ctx, path, client.UploadOptions{Analyze: *analyze})
+		if err == nil && *analyze && *wait {
+			doc, err = c.WaitForAnalysis(ctx, doc.ID, 2*time.Second)
+		}
+		return newResult(path, doc, err)
+	})
+	return report(*format, *out, results)
+}
+
+func runAnalyze(opts options, args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	concurrency := fs.Int("concurrency", 4, "documents analyzed at once")
+	format := fs.String("format", "text", "output format: text, json or csv")
+	fs.Parse(args)
+
+	if opts.local {
+		return errLocalUnsupported
+	}
+
+	// The analyze endpoint answers once analysis is done, so there is
+	// nothing to wait for afterwards.
+	c := newClient(opts)
+	results := each(context.Background(), fs.Args(), *concurrency, func(ctx context.Context, id string) result {
+		doc, err := c.Analyze(ctx, id)
+		return newResult(id, doc, err)
+	})
+	return report(*format, "", results)
+}
+
+func runWait(opts options, args []string) error {
+	fs := flag.NewFlagSet("wait", flag.ExitOnError)
+	timeout := fs.Duration("wait-timeout", 10*time.Minute, "how long to wait in total")
+	interval := fs.Duration("interval", 2*time.Second, "how often to check each document")
+	format := fs.String("format", "text", "output format: text, json or csv")
+	fs.Parse(args)
+
+	if opts.local {
+		return errLocalUnsupported
+	}
+
+	c := newClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	results := each(ctx, fs.Args(), len(fs.Args()), func(ctx context.Context, id string) result {
+		doc, err := c.WaitForAnalysis(ctx, id, *interval)
+		return newResult(id, doc, err)
+	})
+	return report(*format, "", results)
+}
+
+func runGet(opts options, args []string) error {
+	fs := flag.NewFlagSet("get", flag.ExitOnError)
+	format := fs.String("format", "json", "output format: text, json or csv")
+	fs.Parse(args)
+
+	if opts.local {
+		return errLocalUnsupported
+	}
+	return fetch(opts, fs.Args(), *format, "")
+}
+
+func runList(opts options, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	status := fs.String("status", "", "only documents in this status")
+	docType := fs.String("doc-type", "", "only documents of this analyzed type")
+	limit := fs.Int("limit", 50, "documents to list")
+	offset := fs.Int("offset", 0, "documents to skip")
+	format := fs.String("format", "text", "output format: text, json or csv")
+	fs.Parse(args)
+
+	if opts.local {
+		return errLocalUnsupported
+	}
+
+	list, err := newClient(opts).List(context.Background(), client.ListOptions{
+		Status:  client.Status(*status),
+		DocType: *docType,
+		Limit:   *limit,
+		Offset:  *offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	results := make([]result, len(list))
+	for i := range list {
+		results[i] = result{Input: list[i].Filename, Document: &list[i]}
+	}
+	// failed documents are listed, not failures of the command
+	return writeResults(os.Stdout, *format, results)
+}
+
+// runExport writes documents to a file. With --local the arguments are
+// files, which are extracted and analyzed in-process first.
+func runExport(opts options, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "json", "output format: json or csv")
+	out := fs.String("output", "", "write to this file instead of stdout")
+	concurrency := fs.Int("concurrency", 4, "with --local, files analyzed at once")
+	fs.Parse(args)
+
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("export format must be json or csv, got %q", *format)
+	}
+
+	if opts.local {
+		paths, err := expandPaths(fs.Args())
+		if err != nil {
+			return err
+		}
+		local, err := newLocal(true)
+		if err != nil {
+			return err
+		}
+		return report(*format, *out, each(context.Background(), paths, *concurrency, local.process))
+	}
+	return fetch(opts, fs.Args(), *format, *out)
+}
+
+func fetch(opts options, ids []string, format, out string) error {
+	c := newClient(opts)
+	results := each(context.Background(), ids, 4, func(ctx context.Context, id string) result {
+		doc, err := c.Get(ctx, id)
+		return newResult(id, doc, err)
+	})
+	return report(format, out, results)
+}
+
+func newResult(input string, doc *client.Document, err error) result {
+	// a failed analysis shows as the document's status and reason
+	if err != nil && !errors.Is(err, client.ErrAnalysisFailed) {
+		return result{Input: input, Document: doc, Error: err.Error()}
+	}
+	return result{Input: input, Document: doc}
+}
+
+func newClient(opts options) *client.Client {
+	return client.NewClient(opts.server, client.Config{
+		APIKey:     opts.apiKey,
+		HTTPClient: &http.Client{Timeout: opts.timeout},
+	})
+}
+
+// each runs fn over the inputs with at most concurrency calls in flight,
+// keeping results in input order.
+func each(ctx context.Context, inputs []string, concurrency int, fn func(context.Context, string) result) []result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]result, len(inputs))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, input := range inputs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i] = fn(ctx, input)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// report writes the results and fails if any input did.
+func report(format, path string, results []result) error {
+	w, err := output(path)
+	if err != nil {
+		return err
+	}
+	if err := writeResults(w, format, results); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.failed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d failed", failed, len(results))
+	}
+	return nil
+}
+
+// expandPaths turns the arguments into a list of files. Globs are expanded,
+// directories are walked for supported document types, and plain files are
+// taken as given so the server can judge them.
+func expandPaths(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return nil, errors.New("no files given")
+	}
+
+	var paths []string
+	seen := map[string]bool{}
+	add := func(path string) {
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, arg := range args {
+		matches := []string{arg}
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+			if matches, err = filepath.Glob(arg); err != nil {
+				return nil, fmt.Errorf("bad pattern %q: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match %q", arg)
+			}
+		}
+
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			if !info.IsDir() {
+				add(match)
+				continue
+			}
+			err = filepath.WalkDir(match, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if _, ok := upload.TypeOf(path); ok && d.Type().IsRegular() {
+					add(path)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, errors.New("no supported documents found")
+	}
+	return paths, nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/zjoart/docai/internal/documents/extractor"
+)
+
+// runExtract runs the upload extraction on a single file with no server,
+// database or storage, for debugging files that extract badly.
+func runExtract(opts options, args []string) error {
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the whole result as JSON, e.g. for golden files")
+	format := fs.String("format", "", "document format (pdf, docx or txt); defaults to the file extension")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("extract takes exactly one file")
+	}
+	path := fs.Arg(0)
+
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = extractor.FormatOf(path)
+	}
+
+	result, err := extractor.Extract(*format, bytes.NewReader(fileBytes), int64(len(fileBytes)), nil)
+	if err != nil {
+		return fmt.Errorf("extraction failed: %w", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	// The text goes to stdout on its own so it can be piped or diffed.
+	fmt.Fprint(os.Stdout, result.Text)
+	return writeStats(os.Stderr, result)
+}
+
+func writeStats(w io.Writer, result *extractor.Result) error {
+	fmt.Fprintf(w, "\nformat:   %s\nencoding: %s\n\n", result.Format, result.Encoding)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "page\tchars\twords\tlines\tunprintable\t")
+	for _, p := range result.Pages {
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t\n", p.Number, p.Chars, p.Words, p.Lines, p.Unprintable)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/zjoart/docai/internal/config"
+	"github.com/zjoart/docai/internal/documents/analyzer"
+	"github.com/zjoart/docai/internal/documents/extractor"
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/upload"
+	"github.com/zjoart/docai/pkg/client"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+// local extracts, and optionally analyzes, files in-process with the same
+// upload limits, PII policy and model the server is configured with.
+type local struct {
+	policy   upload.Policy
+	pii      pii.Policy
+	analyzer *analyzer.Analyzer
+}
+
+func newLocal(analyze bool) (*local, error) {
+	// Results go to stdout, which is also where the service logs.
+	logger.Log = zap.NewNop()
+
+	cfg, err := config.Parse(flag.NewFlagSet("docai", flag.ContinueOnError), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	l := &local{policy: cfg.UploadPolicy(), pii: pii.Policy{Mode: pii.Mode(cfg.PIIMode)}}
+	for _, kind := range cfg.PIIKinds {
+		l.pii.Kinds = append(l.pii.Kinds, pii.Kind(kind))
+	}
+	if err := errors.Join(l.policy.Validate(), l.pii.Validate()); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if analyze {
+		if cfg.OpenRouterAPIKey == "" {
+			return nil, errors.New("analysis needs OPENROUTER_API_KEY")
+		}
+		l.analyzer = analyzer.NewAnalyzer(cfg.OpenRouterAPIKey, cfg.LLMModel, nil)
+	}
+	return l, nil
+}
+
+// process returns the file as a document that never touched the server: it
+// has no ID, and its status says how far it got.
+func (l *local) process(ctx context.Context, path string) result {
+	fileBytes, err := os.ReadFile(path)
+	if err != nil {
+		return result{Input: path, Error: err.Error()}
+	}
+
+	filename := filepath.Base(path)
+	if err := l.policy.Check(filename, int64(len(fileBytes))); err != nil {
+		return result{Input: path, Error: err.Error()}
+	}
+	contentType, _ := upload.TypeOf(filename)
+
+	doc := &client.Document{
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   int64(len(fileBytes)),
+		Status:      client.StatusUploaded,
+	}
+	if doc.ExtractedText, err = l.extract(filename, fileBytes); err != nil {
+		doc.Status, doc.FailureReason = client.StatusRejected, err.Error()
+		return result{Input: path, Document: doc}
+	}
+
+	if l.analyzer != nil {
+		if err := l.analyze(ctx, doc); err != nil {
+			doc.Status, doc.FailureReason = client.StatusFailed, err.Error()
+		}
+	}
+	return result{Input: path, Document: doc}
+}
+
+func (l *local) extract(filename string, fileBytes []byte) (string, error) {
+	reader := bytes.NewReader(fileBytes)
+	format := extractor.FormatOf(filename)
+	if format == extractor.FormatPDF && l.policy.MaxPages > 0 {
+		pages, err := extractor.CountPDFPages(reader, reader.Size())
+		if err != nil {
+			return "", fmt.Errorf("failed to read PDF: %w", err)
+		}
+		if pages > l.policy.MaxPages {
+			return "", fmt.Errorf("%w (%d, max %d)", upload.ErrTooManyPages, pages, l.policy.MaxPages)
+		}
+	}
+
+	result, err := extractor.Extract(format, reader, reader.Size(), nil)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(result.Text) == "" {
+		return "", errors.New("no text could be extracted from document")
+	}
+	return result.Text, nil
+}
+
+func (l *local) analyze(ctx context.Context, doc *client.Document) error {
+	redaction := pii.Apply(doc.ExtractedText, l.pii)
+	result, err := l.analyzer.AnalyzeText(ctx, redaction.Text)
+	if err != nil {
+		return err
+	}
+
+	doc.Metadata, _ = json.Marshal(redaction.RestoreValue(result.Metadata))
+	doc.Summary = redaction.Restore(result.Summary)
+	doc.DocType = result.Type
+	doc.Status = client.StatusAnalyzed
+	return nil
+}
@@ -0,0 +1,93 @@
+// Command docai is a command-line client for the DocAI API. It uploads files
+// or whole directories, triggers and waits for analysis, and exports results
+// as JSON or CSV. With --local it extracts and analyzes files in-process
+// instead, with no server involved. The operator commands, migrate and
+// rewrap, work on the server's database directly.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+)
+
+const usage = `usage: docai [global flags] <command> [flags] [args]
+
+Commands:
+  upload PATH...   upload files, directories or globs (e.g. 'scans/*.pdf')
+  analyze ID...    analyze uploaded documents
+  wait ID...       wait until documents are no longer queued or processing
+  get ID...        print documents
+  list             list documents, newest first
+  export ID...     write documents as JSON or CSV
+  extract FILE     extract a file's text offline and show page stats and warnings
+
+Operator commands, configured like the server (environment, CONFIG_FILE or
+its flags, e.g. --database-url):
+  migrate CMD      apply, roll back or inspect the database migrations
+  rewrap           re-wrap data keys with the active master key
+
+With --local, upload and export take files instead of IDs and run extraction
+(and with --analyze, analysis) in-process using the server's configuration
+from the environment or CONFIG_FILE.
+
+Global flags:
+`
+
+// options are the global flags shared by every command.
+type options struct {
+	server  string
+	apiKey  string
+	timeout time.Duration
+	local   bool
+}
+
+func main() {
+	var opts options
+	fs := flag.NewFlagSet("docai", flag.ExitOnError)
+	fs.StringVar(&opts.server, "server", envOr("DOCAI_URL", "http://localhost:8080"), "API base URL (DOCAI_URL)")
+	fs.StringVar(&opts.apiKey, "api-key", os.Getenv("DOCAI_API_KEY"), "API key (DOCAI_API_KEY)")
+	fs.DurationVar(&opts.timeout, "timeout", 2*time.Minute, "timeout of each API request")
+	fs.BoolVar(&opts.local, "local", false, "extract and analyze files in-process instead of calling the server")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[1:])
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	commands := map[string]func(options, []string) error{
+		"upload":  runUpload,
+		"analyze": runAnalyze,
+		"wait":    runWait,
+		"get":     runGet,
+		"list":    runList,
+		"export":  runExport,
+		"extract": runExtract,
+		"migrate": runMigrate,
+		"rewrap":  runRewrap,
+	}
+	run, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "docai: unknown command %q\n\n", fs.Arg(0))
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(opts, fs.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "docai: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
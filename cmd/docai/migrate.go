@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/zjoart/docai/internal/config"
+	"github.com/zjoart/docai/internal/database"
+	"github.com/zjoart/docai/migrations"
+)
+
+const migrateUsage = `usage: docai migrate [flags] <command>
+
+Commands:
+  up             apply all pending migrations
+  down [N]       roll back the last N migrations (default 1)
+  status         show the current and latest versions
+  force VERSION  mark VERSION as applied and clean without running it (-1 clears)
+
+Flags are the server's configuration flags, e.g. --database-url.
+`
+
+// runMigrate applies the migrations embedded in the binary. Only the
+// database settings are needed, so the rest of the configuration is not
+// validated.
+func runMigrate(opts options, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(fs.Output(), migrateUsage) }
+
+	cfg, err := config.Parse(fs, args)
+	if err != nil {
+		return fmt.Errorf("invalid config:\n%w", err)
+	}
+	if cfg.DBURL == "" {
+		return errors.New("database_url is required (set DATABASE_URL)")
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	db, err := database.Connect(cfg.DBURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to DB: %w", err)
+	}
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	ctx := context.Background()
+	switch command := fs.Arg(0); command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(os.Stderr, "Applied %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("migration failed: %w", err)
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(os.Stderr, "No pending migrations")
+		}
+
+	case "down":
+		steps := 1
+		if fs.NArg() > 1 {
+			if steps, err = strconv.Atoi(fs.Arg(1)); err != nil || steps < 1 {
+				return fmt.Errorf("down takes a positive number of migrations, got %q", fs.Arg(1))
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Fprintf(os.Stderr, "Rolled back %06d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("rollback failed: %w", err)
+		}
+
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read migration status: %w", err)
+		}
+		fmt.Printf("version: %d\ndirty:   %t\nlatest:  %d\n", status.Version, status.Dirty, status.Latest)
+		for _, m := range status.Pending {
+			fmt.Printf("pending: %06d_%s\n", m.Version, m.Name)
+		}
+
+	case "force":
+		if fs.NArg() < 2 {
+			return errors.New("force needs a version")
+		}
+		version, err := strconv.Atoi(fs.Arg(1))
+		if err != nil {
+			return fmt.Errorf("invalid version %q", fs.Arg(1))
+		}
+		if err := migrator.Force(ctx, version); err != nil {
+			return fmt.Errorf("force failed: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Forced version %d\n", version)
+
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown migrate command %q", command)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/zjoart/docai/pkg/client"
+)
+
+// result is the outcome for one input: a document, an error, or both when a
+// document was created but a later step failed.
+type result struct {
+	Input    string           `json:"input"`
+	Document *client.Document `json:"document,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+func (r result) failed() bool {
+	return r.Error != "" || (r.Document != nil && (r.Document.Status == client.StatusFailed || r.Document.Status == client.StatusRejected))
+}
+
+// writeResults prints results as a table (text), JSON or CSV.
+func writeResults(w io.Writer, format string, results []result) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+
+	case "csv":
+		out := csv.NewWriter(w)
+		out.Write([]string{"input", "id", "filename", "status", "doc_type", "summary", "metadata", "size_bytes", "error"})
+		for _, r := range results {
+			doc := r.Document
+			if doc == nil {
+				doc = &client.Document{}
+			}
+			message := r.Error
+			if message == "" {
+				message = doc.FailureReason
+			}
+			out.Write([]string{r.Input, doc.ID, doc.Filename, string(doc.Status), doc.DocType, doc.Summary, string(doc.Metadata), strconv.FormatInt(doc.SizeBytes, 10), message})
+		}
+		out.Flush()
+		return out.Error()
+
+	case "text", "":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, r := range results {
+			switch {
+			case r.Document == nil:
+				fmt.Fprintf(tw, "-\terror\t%s\t%s\n", r.Input, r.Error)
+			case r.Error != "":
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Document.ID, r.Document.Status, r.Input, r.Error)
+			default:
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Document.ID, r.Document.Status, r.Input, r.Document.FailureReason)
+			}
+		}
+		return tw.Flush()
+
+	default:
+		return fmt.Errorf("unknown format %q (want text, json or csv)", format)
+	}
+}
+
+// output opens the file results go to, stdout for "" or "-".
+func output(path string) (io.WriteCloser, error) {
+	if path == "" || path == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
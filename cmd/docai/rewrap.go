@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/config"
+	"github.com/zjoart/docai/internal/database"
+	"github.com/zjoart/docai/internal/documents"
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/idempotency"
+	"github.com/zjoart/docai/internal/tenant"
+)
+
+// runRewrap re-wraps every data key, those of documents and of stored
+// idempotent responses, with the active master key. Run it after adding a new
+// key to the keyfile and making it active; once it finishes the old key can
+// be removed. Only the wrapped keys change, so files and encrypted columns
+// are not rewritten.
+func runRewrap(opts options, args []string) error {
+	fs := flag.NewFlagSet("rewrap", flag.ExitOnError)
+	batchSize := fs.Int("batch", 500, "keys to load per query")
+	dryRun := fs.Bool("dry-run", false, "report how many keys would be re-wrapped without writing")
+	cfg, err := config.Parse(fs, args)
+	if err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	if cfg.DBURL == "" {
+		return errors.New("database_url is required (set DATABASE_URL)")
+	}
+
+	keys, err := envelope.LoadKeyring(cfg.MasterKey, cfg.MasterKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load master keys: %w", err)
+	}
+	if keys == nil {
+		return errors.New("no MASTER_KEY or MASTER_KEY_FILE configured")
+	}
+
+	db, err := database.Connect(cfg.DBURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to DB: %w", err)
+	}
+
+	ctx := tenant.WithScope(context.Background(), tenant.System)
+	rw := &rewrapper{keys: keys, dryRun: *dryRun}
+
+	docs := documents.NewRepository(db, keys)
+	after := uuid.Nil
+	for {
+		refs, err := docs.ListDataKeys(ctx, after, *batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list document data keys: %w", err)
+		}
+		if len(refs) == 0 {
+			break
+		}
+		for _, ref := range refs {
+			rw.rewrap("document "+ref.ID.String(), ref.DataKey, func(wrapped string) error {
+				return docs.UpdateDataKey(ctx, ref.ID, ref.DataKey, wrapped)
+			})
+		}
+		after = refs[len(refs)-1].ID
+	}
+	fmt.Fprintf(os.Stderr, "Scanned %d documents, re-wrapped %d\n", rw.scanned, rw.rewrapped)
+
+	responses := idempotency.NewRepository(db)
+	rw.scanned, rw.rewrapped = 0, 0
+	var last idempotency.DataKeyRef
+	for {
+		refs, err := responses.ListDataKeys(ctx, last, *batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list idempotency data keys: %w", err)
+		}
+		if len(refs) == 0 {
+			break
+		}
+		for _, ref := range refs {
+			rw.rewrap(fmt.Sprintf("idempotency key %q of tenant %s", ref.Key, ref.TenantID), ref.DataKey, func(wrapped string) error {
+				return responses.UpdateDataKey(ctx, ref, wrapped)
+			})
+		}
+		last = refs[len(refs)-1]
+	}
+	fmt.Fprintf(os.Stderr, "Scanned %d stored responses, re-wrapped %d\n", rw.scanned, rw.rewrapped)
+
+	fmt.Fprintf(os.Stderr, "Active key %q, %d failed\n", keys.ActiveKeyID(), rw.failed)
+	if rw.failed > 0 {
+		return errors.New("some keys could not be re-wrapped; keep the old master keys until they are fixed")
+	}
+	return nil
+}
+
+// rewrapper re-wraps data keys one at a time and counts the outcomes.
+type rewrapper struct {
+	keys   *envelope.Keyring
+	dryRun bool
+
+	scanned, rewrapped, failed int
+}
+
+// rewrap re-wraps a data key that is not wrapped by the active master key
+// and passes it to store, unless this is a dry run.
+func (rw *rewrapper) rewrap(owner, dataKey string, store func(wrapped string) error) {
+	rw.scanned++
+	wrapped, changed, err := rw.keys.Rewrap(dataKey)
+	if err != nil {
+		rw.failed++
+		fmt.Fprintf(os.Stderr, "Failed to re-wrap key of %s: %v\n", owner, err)
+		return
+	}
+	if !changed {
+		return
+	}
+	if !rw.dryRun {
+		if err := store(wrapped); err != nil {
+			rw.failed++
+			fmt.Fprintf(os.Stderr, "Failed to store re-wrapped key of %s: %v\n", owner, err)
+			return
+		}
+	}
+	rw.rewrapped++
+}
@@ -1,51 +1,287 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/auth"
 	"github.com/zjoart/docai/internal/config"
 	"github.com/zjoart/docai/internal/database"
 	"github.com/zjoart/docai/internal/documents"
 	"github.com/zjoart/docai/internal/documents/analyzer"
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/grpcserver"
+	"github.com/zjoart/docai/internal/health"
+	"github.com/zjoart/docai/internal/idempotency"
+	"github.com/zjoart/docai/internal/metrics"
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/requestlog"
+	"github.com/zjoart/docai/internal/scanner"
 	"github.com/zjoart/docai/internal/storage"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/internal/tracing"
+	"github.com/zjoart/docai/internal/upload"
+	"github.com/zjoart/docai/internal/webhooks"
+	"github.com/zjoart/docai/migrations"
+	docaiv1 "github.com/zjoart/docai/pkg/api/docai/v1"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
+	printConfig := flag.Bool("print-config", false, "print the effective configuration, secrets redacted, and exit")
+	cfg, err := config.LoadFlags(flag.CommandLine, os.Args[1:])
+	if *printConfig && cfg != nil {
+		if printErr := cfg.Print(os.Stdout); printErr != nil {
+			log.Fatalf("Failed to print config: %v", printErr)
+		}
+	}
+	if err != nil {
+		log.Fatalf("Invalid config:\n%v", err)
+	}
+	if *printConfig {
+		return
+	}
 
-	cfg, err := config.Load()
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    cfg.TracesExporter,
+		ServiceName: cfg.ServiceName,
+	})
 	if err != nil {
-		log.Fatalf("Failed to load config: %v", err)
+		log.Fatalf("Failed to init tracing: %v", err)
 	}
+	defer shutdownTracing(context.Background())
 
 	db, err := database.Connect(cfg.DBURL)
 	if err != nil {
 		log.Fatalf("Failed to connect to DB: %v", err)
 	}
+	if err := tracing.InstrumentGORM(db); err != nil {
+		log.Fatalf("Failed to instrument DB: %v", err)
+	}
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	if cfg.AutoMigrate {
+		applied, err := migrator.Up(context.Background())
+		for _, m := range applied {
+			log.Printf("Applied migration %06d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatalf("Failed to migrate DB: %v", err)
+		}
+	}
+	if err := migrator.Check(context.Background()); err != nil {
+		log.Fatalf("Refusing to start: %v; run `docai migrate up` or set AUTO_MIGRATE=true", err)
+	}
 
 	minioClient, err := storage.NewMinioClient(cfg.MinioEndpoint, cfg.MinioAccessKey, cfg.MinioSecretKey, cfg.MinioBucket)
 	if err != nil {
 		log.Fatalf("Failed to init Minio: %v", err)
 	}
 
-	aiAnalyzer := analyzer.NewAnalyzer(cfg.OpenRouterAPIKey)
+	metricsRegistry := metrics.New()
+	aiAnalyzer := analyzer.NewAnalyzer(cfg.OpenRouterAPIKey, cfg.LLMModel, metricsRegistry)
+
+	tenants := tenant.NewRepository(db)
+	quotas := quota.NewService(quota.NewRepository(db), tenants, quota.Limits{
+		RequestsPerMinute: cfg.QuotaRequestsPerMinute,
+		DailyAnalyses:     cfg.QuotaDailyAnalyses,
+		StorageBytes:      cfg.QuotaStorageBytes,
+		MonthlyTokens:     cfg.QuotaMonthlyTokens,
+	})
+
+	piiPolicy := pii.Policy{Mode: pii.Mode(cfg.PIIMode)}
+	for _, kind := range cfg.PIIKinds {
+		piiPolicy.Kinds = append(piiPolicy.Kinds, pii.Kind(kind))
+	}
+
+	keys, err := envelope.LoadKeyring(cfg.MasterKey, cfg.MasterKeyFile)
+	if err != nil {
+		log.Fatalf("Failed to load master keys: %v", err)
+	}
+	if keys == nil {
+		log.Printf("WARNING: no MASTER_KEY or MASTER_KEY_FILE set, documents are stored unencrypted")
+	}
+
+	var scan scanner.Scanner
+	if cfg.ClamdAddr != "" {
+		scan = scanner.NewClamd(cfg.ClamdAddr, cfg.ScanTimeout)
+	} else {
+		log.Printf("WARNING: no CLAMD_ADDR set, uploads are not scanned for malware")
+	}
+
+	auditService := audit.NewService(audit.NewRepository(db))
+
+	bus := events.NewBus()
+	repo := documents.NewRepository(db, keys)
+	svc := documents.NewService(repo, minioClient, aiAnalyzer, bus, quotas, pii.NewRedactor(tenants, piiPolicy), keys, scan, auditService, metricsRegistry, upload.NewPolicies(tenants, cfg.UploadPolicy()))
+	batches := documents.NewBatchRunner(svc, repo, documents.BatchConfig{
+		Workers:       cfg.BatchWorkers,
+		RatePerSecond: cfg.LLMRateLimit,
+	})
+	// background loops run until shutdown
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
 
-	repo := documents.NewRepository(db)
-	svc := documents.NewService(repo, minioClient, aiAnalyzer)
-	handler := documents.NewHandler(svc)
+	idempotent := idempotency.NewService(idempotency.NewRepository(db), keys, idempotency.Config{
+		TTL:         cfg.IdempotencyTTL,
+		LockTimeout: cfg.IdempotencyLockTimeout,
+	})
+	go idempotent.Run(background)
+	handler := documents.NewHandler(svc, batches, documents.HandlerConfig{MultipartMemory: cfg.UploadMemoryBytes, Idempotency: idempotent})
+
+	metricsRegistry.RegisterQueueDepth(batches.QueueDepth)
+	metricsRegistry.RegisterStatusCounts(func(ctx context.Context) (map[string]int64, error) {
+		counts, err := svc.CountByStatus(tenant.WithScope(ctx, tenant.System))
+		byStatus := make(map[string]int64, len(counts))
+		for status, n := range counts {
+			byStatus[string(status)] = n
+		}
+		return byStatus, err
+	})
+
+	webhookRepo := webhooks.NewRepository(db)
+	dispatcher := webhooks.NewDispatcher(webhookRepo, bus, webhooks.Config{
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		BaseBackoff: 5 * time.Second,
+		MaxBackoff:  time.Hour,
+		Timeout:     cfg.WebhookTimeout,
+
+		AllowPrivateNetworks: cfg.WebhookAllowPrivate,
+	})
+	dispatcherStopped := make(chan struct{})
+	go func() {
+		defer close(dispatcherStopped)
+		dispatcher.Run(background)
+	}()
+	webhookHandler := webhooks.NewHandler(webhooks.NewService(webhookRepo, dispatcher))
+
+	authService, err := auth.NewService(auth.NewRepository(db), tenants, auth.Config{
+		BootstrapKey: cfg.AdminAPIKey,
+		Quotas:       quotas,
+		JWT: auth.JWTConfig{
+			Secret:   cfg.JWTSecret,
+			JWKSFile: cfg.JWTJWKSFile,
+			Issuer:   cfg.JWTIssuer,
+			Audience: cfg.JWTAudience,
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to init auth: %v", err)
+	}
 
 	r := mux.NewRouter()
-	documents.RegisterRoutes(r, handler)
+	r.Use(metricsRegistry.Middleware, tracing.RouteMiddleware)
+	r.Handle("/metrics", metricsRegistry.Handler()).Methods("GET")
+
+	probes := health.NewService(cfg.ReadyTimeout)
+	probes.Register("database", func(ctx context.Context) error { return database.Ping(ctx, db) })
+	probes.Register("storage", minioClient.CheckBucket)
+	if cfg.ReadyCheckLLM {
+		probes.Register("llm", aiAnalyzer.Ping)
+	}
+	health.RegisterRoutes(r, probes)
+
+	api := r.NewRoute().Subrouter()
+	api.Use(authService.Middleware, quotas.Middleware, audit.Middleware)
+	documents.RegisterRoutes(api, handler)
+	webhooks.RegisterRoutes(api, webhookHandler)
+	auth.RegisterRoutes(api, auth.NewHandler(authService))
+	audit.RegisterRoutes(api, audit.NewHandler(auditService))
 
 	r.PathPrefix("/docs/").Handler(http.StripPrefix("/docs/", http.FileServer(http.Dir("./docs"))))
 	r.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/docs/swagger.yaml"),
 	))
 
+	if err := svc.ResumeQueued(context.Background()); err != nil {
+		log.Printf("Failed to resume queued analyses: %v", err)
+	}
+
+	srv := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      tracing.Handler(requestlog.Middleware(r)),
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+	srv.RegisterOnShutdown(handler.CloseStreams)
+
+	grpcServer := grpcserver.NewServer(authService, quotas)
+	grpcDocuments := documents.NewGRPCServer(svc, batches)
+	docaiv1.RegisterDocumentServiceServer(grpcServer, grpcDocuments)
+	grpcHealth := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, grpcHealth)
+
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+	go func() {
+		log.Printf("gRPC server starting on port %s", cfg.GRPCPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatalf("gRPC server failed: %v", err)
+		}
+	}()
+
+	stopped := make(chan struct{})
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		defer close(stopped)
+		<-stop
+		// fail readiness first so the orchestrator stops routing here
+		log.Printf("Shutting down: draining for %s", cfg.ShutdownDrainDelay)
+		probes.Drain()
+		grpcHealth.Shutdown()
+		time.Sleep(cfg.ShutdownDrainDelay)
+
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("Shutdown failed to finish in-flight requests: %v", err)
+		}
+		grpcDocuments.CloseStreams()
+		grpcStopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(grpcStopped)
+		}()
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			log.Printf("Shutdown failed to finish in-flight gRPC calls: %v", ctx.Err())
+			grpcServer.Stop()
+		}
+		// analyses still running past the deadline are requeued
+		if err := svc.Shutdown(ctx); err != nil {
+			log.Printf("Shutdown interrupted background analyses: %v", err)
+		}
+		// only now, so the events of the last analyses become deliveries
+		stopBackground()
+		<-dispatcherStopped
+	}()
+
 	log.Printf("Server starting on port %s", cfg.Port)
-	if err := http.ListenAndServe(":"+cfg.Port, r); err != nil {
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		log.Fatalf("Server failed: %v", err)
 	}
+	<-stopped
+	log.Printf("Server stopped")
 }
@@ -0,0 +1,138 @@
+// Package apierror defines the errors the API reports to clients. Each error
+// has a stable code, and the code alone decides the HTTP status and gRPC code
+// it is reported with, so every handler answers the same failure the same way.
+package apierror
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Code identifies the kind of error. Codes are part of the API: clients may
+// branch on them, so they never change once released.
+type Code string
+
+const (
+	CodeBadRequest       Code = "bad_request"
+	CodeUnauthorized     Code = "unauthorized"
+	CodeForbidden        Code = "forbidden"
+	CodeNotFound         Code = "not_found"
+	CodeConflict         Code = "conflict"
+	CodeTooLarge         Code = "too_large"
+	CodeUnsupported      Code = "unsupported_type"
+	CodeExtractionFailed Code = "extraction_failed"
+	CodeMalwareDetected  Code = "malware_detected"
+	CodeQuotaExceeded    Code = "quota_exceeded"
+	CodeInternal         Code = "internal"
+	CodeUpstreamLLM      Code = "upstream_llm"
+	CodeUnavailable      Code = "unavailable"
+
+	// CodeIdempotencyKeyInUse means an earlier request with the same
+	// Idempotency-Key is still running; CodeIdempotencyKeyReused that the key
+	// was used for a different request.
+	CodeIdempotencyKeyInUse  Code = "idempotency_key_in_use"
+	CodeIdempotencyKeyReused Code = "idempotency_key_reused"
+)
+
+// mapping is the one place codes are turned into statuses.
+var mapping = map[Code]struct {
+	http int
+	grpc codes.Code
+}{
+	CodeBadRequest:       {http.StatusBadRequest, codes.InvalidArgument},
+	CodeUnauthorized:     {http.StatusUnauthorized, codes.Unauthenticated},
+	CodeForbidden:        {http.StatusForbidden, codes.PermissionDenied},
+	CodeNotFound:         {http.StatusNotFound, codes.NotFound},
+	CodeConflict:         {http.StatusConflict, codes.FailedPrecondition},
+	CodeTooLarge:         {http.StatusRequestEntityTooLarge, codes.InvalidArgument},
+	CodeUnsupported:      {http.StatusUnsupportedMediaType, codes.InvalidArgument},
+	CodeExtractionFailed: {http.StatusUnprocessableEntity, codes.InvalidArgument},
+	CodeMalwareDetected:  {http.StatusUnprocessableEntity, codes.FailedPrecondition},
+	CodeQuotaExceeded:    {http.StatusTooManyRequests, codes.ResourceExhausted},
+	CodeInternal:         {http.StatusInternalServerError, codes.Internal},
+	CodeUpstreamLLM:      {http.StatusBadGateway, codes.Unavailable},
+	CodeUnavailable:      {http.StatusServiceUnavailable, codes.Unavailable},
+
+	CodeIdempotencyKeyInUse:  {http.StatusConflict, codes.Aborted},
+	CodeIdempotencyKeyReused: {http.StatusUnprocessableEntity, codes.InvalidArgument},
+}
+
+// HTTPStatus is the response status for code; unknown codes are 500.
+func (c Code) HTTPStatus() int {
+	if m, ok := mapping[c]; ok {
+		return m.http
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode is the gRPC status code for code; unknown codes are Internal.
+func (c Code) GRPCCode() codes.Code {
+	if m, ok := mapping[c]; ok {
+		return m.grpc
+	}
+	return codes.Internal
+}
+
+// Error is an error that may be shown to clients. Message is safe to return;
+// the wrapped Err is only logged.
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+	// RetryAfter, when set, tells the client how long to wait before trying
+	// again.
+	RetryAfter time.Duration
+	// Details are extra members of the response, e.g. the quota limit that
+	// was hit.
+	Details map[string]interface{}
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap returns an error with the code and public message that keeps err as
+// its cause, so errors.Is and errors.As still see it.
+func Wrap(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func BadRequest(message string) *Error   { return New(CodeBadRequest, message) }
+func Unauthorized(message string) *Error { return New(CodeUnauthorized, message) }
+func Forbidden(message string) *Error    { return New(CodeForbidden, message) }
+func NotFound(message string) *Error     { return New(CodeNotFound, message) }
+func Conflict(message string) *Error     { return New(CodeConflict, message) }
+func Unsupported(message string) *Error  { return New(CodeUnsupported, message) }
+func TooLarge(message string) *Error     { return New(CodeTooLarge, message) }
+func Internal(message string) *Error     { return New(CodeInternal, message) }
+func Unavailable(message string) *Error  { return New(CodeUnavailable, message) }
+
+// As returns the outermost *Error in err's chain.
+func As(err error) (*Error, bool) {
+	var e *Error
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+// From returns the *Error in err's chain, or an internal error with a generic
+// message for errors that were never meant for clients.
+func From(err error) *Error {
+	if e, ok := As(err); ok {
+		return e
+	}
+	return Wrap(CodeInternal, "Internal server error", err)
+}
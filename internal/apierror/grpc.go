@@ -0,0 +1,30 @@
+package apierror
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/zjoart/docai/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCError converts err for a gRPC response the way Write does for HTTP.
+// Errors that already carry a gRPC status, such as those of the stream
+// itself, are returned unchanged.
+func GRPCError(ctx context.Context, err error) error {
+	e, ok := As(err)
+	if !ok {
+		if _, isStatus := status.FromError(err); isStatus {
+			return err
+		}
+		e = From(err)
+		logger.FromContext(ctx).Error("Request failed", logger.WithError(err))
+	}
+
+	if e.RetryAfter > 0 {
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfterSeconds(e))))
+	}
+	return status.Error(e.Code.GRPCCode(), e.Message)
+}
@@ -0,0 +1,97 @@
+package apierror
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+// ContentType is the media type of problem responses.
+const ContentType = "application/problem+json"
+
+// TypePrefix starts the type URI of every problem; the code follows it.
+const TypePrefix = "urn:docai:problem:"
+
+// Problem is an RFC 7807 problem details response.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      Code   `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// Extensions are added as further members of the object.
+	Extensions map[string]interface{} `json:"-"`
+}
+
+// NewProblem describes err as a problem for the response to r. Errors that
+// are not an *Error are logged and described only as an internal error.
+func NewProblem(r *http.Request, err error) *Problem {
+	e := From(err)
+	if e.Code == CodeInternal && e.Err != nil {
+		logger.FromContext(r.Context()).Error("Request failed", logger.Merge(logger.Fields{"path": r.URL.Path}, logger.WithError(e.Err)))
+	}
+
+	status := e.Code.HTTPStatus()
+	p := &Problem{
+		Type:       TypePrefix + string(e.Code),
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     e.Message,
+		Instance:   r.URL.Path,
+		Code:       e.Code,
+		RequestID:  logger.RequestIDFromContext(r.Context()),
+		Extensions: map[string]interface{}{},
+	}
+	for k, v := range e.Details {
+		p.Extensions[k] = v
+	}
+	if e.RetryAfter > 0 {
+		p.Extensions["retry_after"] = retryAfterSeconds(e)
+	}
+	return p
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	data, err := json.Marshal((*problem)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return data, err
+	}
+
+	members := map[string]interface{}{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for k, v := range p.Extensions {
+		// extensions never replace the standard members
+		if _, ok := members[k]; !ok {
+			members[k] = v
+		}
+	}
+	return json.Marshal(members)
+}
+
+// Write responds to r with err as a problem, setting Retry-After when the
+// error asks for one.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	WriteProblem(w, NewProblem(r, err))
+}
+
+func WriteProblem(w http.ResponseWriter, p *Problem) {
+	if seconds, ok := p.Extensions["retry_after"].(int); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// retryAfterSeconds rounds RetryAfter up to whole seconds, at least one.
+func retryAfterSeconds(e *Error) int {
+	return int(math.Max(1, math.Ceil(e.RetryAfter.Seconds())))
+}
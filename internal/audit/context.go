@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Request identifies who made a request, so events recorded while serving it
+// can be attributed.
+type Request struct {
+	Actor     string
+	ActorType string
+	IP        string
+	RequestID string
+}
+
+type contextKey struct{}
+
+func WithRequest(ctx context.Context, req Request) context.Context {
+	return context.WithValue(ctx, contextKey{}, req)
+}
+
+func RequestFromContext(ctx context.Context) (Request, bool) {
+	req, ok := ctx.Value(contextKey{}).(Request)
+	return req, ok
+}
+
+// Detach is tenant.Detach that also keeps the request attribution, the
+// logging IDs and the trace, so work started by a request and finished in the
+// background is audited, logged and traced under the request that started it.
+func Detach(ctx context.Context) context.Context {
+	detached := logger.Inherit(tenant.Detach(ctx), ctx)
+	detached = trace.ContextWithSpanContext(detached, trace.SpanContextFromContext(ctx))
+	if req, ok := RequestFromContext(ctx); ok {
+		detached = WithRequest(detached, req)
+	}
+	return detached
+}
@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/pkg/id"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+type Handler struct {
+	service *Service
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListEvents returns the most recent audit events of the caller's tenant.
+func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r.URL.Query())
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+
+	filter.Limit = defaultListLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			apierror.Write(w, r, apierror.BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxListLimit)))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	list, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// ExportEvents streams every matching audit event as JSON lines, oldest
+// first. The export itself is audited once it has finished, with its outcome.
+func (h *Handler) ExportEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseFilter(r.URL.Query())
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+
+	started := time.Now()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit.jsonl"`)
+
+	enc := json.NewEncoder(w)
+	exported := 0
+	err = h.service.Export(r.Context(), filter, func(e Event) error {
+		exported++
+		return enc.Encode(e)
+	})
+
+	event := Event{
+		Action:    ActionExport,
+		Outcome:   OutcomeSuccess,
+		Detail:    r.URL.RawQuery,
+		CreatedAt: started,
+	}
+	if err != nil {
+		event.Outcome = OutcomeFailure
+		event.Detail = fmt.Sprintf("%s: %v", r.URL.RawQuery, err)
+	}
+	h.service.Record(r.Context(), event)
+
+	if err == nil {
+		return
+	}
+	if exported == 0 {
+		w.Header().Del("Content-Disposition")
+		apierror.Write(w, r, err)
+		return
+	}
+	// the status line is gone once rows are streamed; truncate the export
+	logger.FromContext(r.Context()).Error("Audit export failed", logger.Merge(logger.Fields{"exported": exported}, logger.WithError(err)))
+}
+
+func parseFilter(q url.Values) (Filter, error) {
+	filter := Filter{
+		Action:  Action(q.Get("action")),
+		Actor:   q.Get("actor"),
+		Outcome: Outcome(q.Get("outcome")),
+	}
+
+	if raw := q.Get("document_id"); raw != "" {
+		docID, err := id.IsValidUUID(raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid document_id")
+		}
+		filter.DocumentID = &docID
+	}
+
+	for name, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, fmt.Errorf("%s must be an RFC 3339 timestamp", name)
+		}
+		*dst = &t
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/auth"
+	"github.com/zjoart/docai/internal/httputil"
+	"github.com/zjoart/docai/pkg/id"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+// Middleware attaches the caller, client IP and request ID to the request
+// context. It must run after authentication and the request ID middleware.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := Request{IP: clientIP(r), RequestID: logger.RequestIDFromContext(r.Context())}
+		if principal, ok := auth.FromContext(r.Context()); ok {
+			req.Actor = principal.Subject
+			req.ActorType = principal.Method
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithRequest(r.Context(), req)))
+	})
+}
+
+// Track records action against the document named by the route's {id}
+// variable once the handler returns, with the outcome taken from the response
+// status. It wraps the scope check so refused requests are recorded too.
+func (s *Service) Track(action Action, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		rec := httputil.NewStatusRecorder(w)
+		next(rec, r)
+
+		event := Event{
+			Action:    action,
+			Outcome:   outcomeForStatus(rec.Status()),
+			CreatedAt: started,
+		}
+		if docID, err := id.IsValidUUID(mux.Vars(r)["id"]); err == nil {
+			event.DocumentID = &docID
+		}
+		if event.Outcome != OutcomeSuccess {
+			event.Detail = http.StatusText(rec.Status())
+		}
+		s.Record(r.Context(), event)
+	}
+}
+
+func outcomeForStatus(status int) Outcome {
+	switch {
+	case status < 400:
+		return OutcomeSuccess
+	case status == http.StatusUnauthorized, status == http.StatusForbidden, status == http.StatusNotFound:
+		return OutcomeDenied
+	default:
+		return OutcomeFailure
+	}
+}
+
+// clientIP is the peer address of the connection. Forwarding headers are
+// ignored because clients can set them to anything.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Action is what was done to a document.
+type Action string
+
+const (
+	ActionView     Action = "document.view"
+	ActionDownload Action = "document.download"
+	ActionUpload   Action = "document.upload"
+	ActionAnalyze  Action = "document.analyze"
+	ActionExport   Action = "audit.export"
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	// OutcomeDenied covers requests refused for lack of credentials, scope or
+	// visibility of the document.
+	OutcomeDenied  Outcome = "denied"
+	OutcomeFailure Outcome = "failure"
+)
+
+// ActorSystem is recorded for work no request can be attributed to.
+const ActorSystem = "system"
+
+// Event is one row of the append-only audit log.
+type Event struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	Actor      string     `json:"actor"`
+	ActorType  string     `json:"actor_type"`
+	Action     Action     `json:"action"`
+	DocumentID *uuid.UUID `gorm:"type:uuid" json:"document_id,omitempty"`
+	IP         string     `json:"ip,omitempty"`
+	RequestID  string     `json:"request_id,omitempty"`
+	Outcome    Outcome    `json:"outcome"`
+	Detail     string     `json:"detail,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (Event) TableName() string {
+	return "audit_events"
+}
+
+func (e *Event) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return
+}
+
+// Filter selects audit events. Zero fields match everything.
+type Filter struct {
+	Action     Action
+	Actor      string
+	Outcome    Outcome
+	DocumentID *uuid.UUID
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+}
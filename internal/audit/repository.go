@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/zjoart/docai/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// Repository only appends and reads; the table also rejects updates and
+// deletes with a trigger.
+type Repository interface {
+	Create(ctx context.Context, event *Event) error
+	List(ctx context.Context, filter Filter) ([]Event, error)
+	// Each calls fn for every matching event, oldest first, without loading
+	// them all into memory.
+	Each(ctx context.Context, filter Filter, fn func(Event) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, event *Event) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		if !scope.Allows(event.TenantID, "") {
+			return tenant.ErrOutOfScope
+		}
+		return tx.Create(event).Error
+	})
+}
+
+func (r *repository) List(ctx context.Context, filter Filter) ([]Event, error) {
+	var list []Event
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		query := applyFilter(scope.FilterTenant(tx), filter).Order("created_at DESC, id DESC")
+		if filter.Limit > 0 {
+			query = query.Limit(filter.Limit)
+		}
+		return query.Find(&list).Error
+	})
+	return list, err
+}
+
+func (r *repository) Each(ctx context.Context, filter Filter, fn func(Event) error) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		rows, err := applyFilter(scope.FilterTenant(tx.Model(&Event{})), filter).Order("created_at, id").Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var event Event
+			if err := tx.ScanRows(rows, &event); err != nil {
+				return err
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	})
+}
+
+func applyFilter(tx *gorm.DB, f Filter) *gorm.DB {
+	if f.Action != "" {
+		tx = tx.Where("action = ?", f.Action)
+	}
+	if f.Actor != "" {
+		tx = tx.Where("actor = ?", f.Actor)
+	}
+	if f.Outcome != "" {
+		tx = tx.Where("outcome = ?", f.Outcome)
+	}
+	if f.DocumentID != nil {
+		tx = tx.Where("document_id = ?", *f.DocumentID)
+	}
+	if f.Since != nil {
+		tx = tx.Where("created_at >= ?", *f.Since)
+	}
+	if f.Until != nil {
+		tx = tx.Where("created_at < ?", *f.Until)
+	}
+	return tx
+}
@@ -0,0 +1,11 @@
+package audit
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/auth"
+)
+
+func RegisterRoutes(r *mux.Router, h *Handler) {
+	r.HandleFunc("/audit", auth.RequireScope(auth.ScopeAdmin, h.ListEvents)).Methods("GET")
+	r.HandleFunc("/audit/export", auth.RequireScope(auth.ScopeAdmin, h.ExportEvents)).Methods("GET")
+}
@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Record appends an event attributed to the request in ctx, or to the system
+// when there is none. TenantID defaults to the tenant scope of ctx. A failed
+// write is logged rather than failing the audited operation.
+func (s *Service) Record(ctx context.Context, event Event) {
+	if req, ok := RequestFromContext(ctx); ok {
+		event.Actor = req.Actor
+		event.ActorType = req.ActorType
+		event.IP = req.IP
+		event.RequestID = req.RequestID
+	} else {
+		event.Actor = ActorSystem
+		event.ActorType = ActorSystem
+	}
+
+	if event.TenantID == "" {
+		if scope, ok := tenant.FromContext(ctx); ok && !scope.IsSystem() {
+			event.TenantID = scope.TenantID
+		}
+	}
+	if event.TenantID == "" {
+		logger.Error("Dropping audit event without tenant", logger.Fields{"action": event.Action, "actor": event.Actor})
+		return
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	// the caller may already have gone away; the record must still be written
+	ctx = tenant.WithScope(context.WithoutCancel(ctx), tenant.Scope{TenantID: event.TenantID})
+	if err := s.repo.Create(ctx, &event); err != nil {
+		logger.Error("Failed to write audit event", logger.Merge(logger.Fields{
+			"action":      event.Action,
+			"actor":       event.Actor,
+			"document_id": event.DocumentID,
+			"request_id":  event.RequestID,
+		}, logger.WithError(err)))
+	}
+}
+
+// List returns the most recent matching events in the caller's tenant.
+func (s *Service) List(ctx context.Context, filter Filter) ([]Event, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// Export calls fn for every matching event in the caller's tenant, oldest first.
+func (s *Service) Export(ctx context.Context, filter Filter, fn func(Event) error) error {
+	return s.repo.Each(ctx, filter, fn)
+}
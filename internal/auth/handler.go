@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/internal/upload"
+	"github.com/zjoart/docai/pkg/id"
+)
+
+type Handler struct {
+	service *Service
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+type createKeyRequest struct {
+	// TenantID may only be set by platform principals; it defaults to the
+	// caller's own tenant.
+	TenantID  string     `json:"tenant_id,omitempty"`
+	Name      string     `json:"name"`
+	Scopes    []Scope    `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (h *Handler) CreateKey(w http.ResponseWriter, r *http.Request) {
+	var req createKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	principal, _ := FromContext(r.Context())
+	tenantID := principal.TenantID
+	if req.TenantID != "" && req.TenantID != tenantID {
+		if !principal.Platform {
+			apierror.Write(w, r, apierror.Forbidden("Cannot create keys for another tenant"))
+			return
+		}
+		tenantID = req.TenantID
+	}
+
+	key, raw, err := h.service.CreateKey(r.Context(), tenantID, req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, ErrInvalidScopes) || errors.Is(err, ErrInvalidKeyName) || errors.Is(err, ErrUnknownTenant) {
+			apierror.Write(w, r, apierror.BadRequest(err.Error()))
+			return
+		}
+		apierror.Write(w, r, apierror.Internal("Failed to create API key"))
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"api_key": key,
+		"key":     raw,
+	})
+}
+
+func (h *Handler) ListKeys(w http.ResponseWriter, r *http.Request) {
+	principal, _ := FromContext(r.Context())
+
+	keys, err := h.service.ListKeys(r.Context(), principal.TenantID)
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal("Failed to list API keys"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, keys)
+}
+
+func (h *Handler) RevokeKey(w http.ResponseWriter, r *http.Request) {
+	keyID, err := id.IsValidUUID(mux.Vars(r)["id"])
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid key ID format"))
+		return
+	}
+
+	principal, _ := FromContext(r.Context())
+
+	if err := h.service.RevokeKey(r.Context(), principal.TenantID, keyID); err != nil {
+		if h.service.IsNotFoundError(err) {
+			apierror.Write(w, r, apierror.NotFound("API key not found or already revoked"))
+			return
+		}
+		apierror.Write(w, r, apierror.Internal("Failed to revoke API key"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type createTenantRequest struct {
+	ID     string        `json:"id"`
+	Name   string        `json:"name"`
+	Limits tenant.Limits `json:"limits"`
+}
+
+func (h *Handler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	var req createTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	t, err := h.service.CreateTenant(r.Context(), req.ID, req.Name, req.Limits)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidTenant), errors.Is(err, ErrInvalidLimits):
+			apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		case errors.Is(err, ErrTenantExists):
+			apierror.Write(w, r, apierror.Conflict(err.Error()))
+		default:
+			apierror.Write(w, r, apierror.Internal("Failed to create tenant"))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, t)
+}
+
+func (h *Handler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.service.ListTenants(r.Context())
+	if err != nil {
+		apierror.Write(w, r, apierror.Internal("Failed to list tenants"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tenants)
+}
+
+func (h *Handler) UpdateTenantLimits(w http.ResponseWriter, r *http.Request) {
+	var limits tenant.Limits
+	if err := json.NewDecoder(r.Body).Decode(&limits); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	t, err := h.service.UpdateTenantLimits(r.Context(), mux.Vars(r)["id"], limits)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidLimits):
+			apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		case h.service.IsTenantNotFoundError(err):
+			apierror.Write(w, r, apierror.NotFound("Tenant not found"))
+		default:
+			apierror.Write(w, r, apierror.Internal("Failed to update tenant limits"))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+// UpdateTenantPIIPolicy accepts a policy object, or null to use the default.
+func (h *Handler) UpdateTenantPIIPolicy(w http.ResponseWriter, r *http.Request) {
+	var policy *pii.Policy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	t, err := h.service.UpdateTenantPIIPolicy(r.Context(), mux.Vars(r)["id"], policy)
+	if err != nil {
+		switch {
+		case errors.Is(err, pii.ErrInvalidPolicy):
+			apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		case h.service.IsTenantNotFoundError(err):
+			apierror.Write(w, r, apierror.NotFound("Tenant not found"))
+		default:
+			apierror.Write(w, r, apierror.Internal("Failed to update tenant PII policy"))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+// UpdateTenantUploadPolicy accepts a policy object, or null to use the default.
+func (h *Handler) UpdateTenantUploadPolicy(w http.ResponseWriter, r *http.Request) {
+	var policy *upload.Policy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	t, err := h.service.UpdateTenantUploadPolicy(r.Context(), mux.Vars(r)["id"], policy)
+	if err != nil {
+		switch {
+		case errors.Is(err, upload.ErrInvalidPolicy):
+			apierror.Write(w, r, apierror.BadRequest(err.Error()))
+		case h.service.IsTenantNotFoundError(err):
+			apierror.Write(w, r, apierror.NotFound("Tenant not found"))
+		default:
+			apierror.Write(w, r, apierror.Internal("Failed to update tenant upload policy"))
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
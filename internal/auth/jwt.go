@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type JWTConfig struct {
+	// Secret enables HS256 tokens.
+	Secret string
+	// JWKSFile is a path to a JSON Web Key Set used to verify RS* and ES* tokens.
+	JWKSFile string
+	Issuer   string
+	Audience string
+}
+
+type tokenClaims struct {
+	TenantID string   `json:"tenant_id"`
+	Scope    string   `json:"scope"`
+	Scopes   []string `json:"scopes"`
+	jwt.RegisteredClaims
+}
+
+type jwtVerifier struct {
+	parser *jwt.Parser
+	secret []byte
+	keys   map[string]interface{}
+}
+
+// newJWTVerifier returns nil when neither a secret nor a JWKS file is
+// configured, in which case bearer tokens are rejected.
+func newJWTVerifier(cfg JWTConfig) (*jwtVerifier, error) {
+	if cfg.Secret == "" && cfg.JWKSFile == "" {
+		return nil, nil
+	}
+
+	v := &jwtVerifier{}
+	var methods []string
+
+	if cfg.Secret != "" {
+		v.secret = []byte(cfg.Secret)
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+		methods = append(methods, "RS256", "RS384", "RS512", "ES256", "ES384", "ES512")
+	}
+
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+func (v *jwtVerifier) Verify(raw string) (*Principal, error) {
+	var claims tokenClaims
+	if _, err := v.parser.ParseWithClaims(raw, &claims, v.keyFunc); err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+	if claims.TenantID == "" {
+		return nil, errors.New("token has no tenant_id")
+	}
+	// this also rules out "*", which would be the system scope
+	if !validTenantID.MatchString(claims.TenantID) {
+		return nil, fmt.Errorf("token has an invalid tenant_id %q", claims.TenantID)
+	}
+
+	scopes := ScopeList{}
+	for _, s := range append(strings.Fields(claims.Scope), claims.Scopes...) {
+		if validScopes[Scope(s)] {
+			scopes = append(scopes, Scope(s))
+		}
+	}
+
+	return &Principal{
+		Subject:  claims.Subject,
+		Method:   MethodJWT,
+		TenantID: claims.TenantID,
+		Scopes:   scopes,
+	}, nil
+}
+
+func (v *jwtVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return v.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func loadJWKS(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS file: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q in JWKS: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS file contains no signing keys")
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/tenant"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// Middleware authenticates every request with an API key (X-API-Key header
+// or bearer token) or a JWT bearer token, and stores the principal and its
+// tenant scope in the request context.
+func (s *Service) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credential := credentialFromRequest(r)
+		if credential == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="docai"`)
+			apierror.Write(w, r, apierror.Unauthorized("Authentication required"))
+			return
+		}
+
+		principal, err := s.Authenticate(r.Context(), credential)
+		var scope tenant.Scope
+		if err == nil {
+			scope, err = principal.TenantScope()
+		}
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="docai", error="invalid_token"`)
+			apierror.Write(w, r, apierror.Unauthorized("Invalid credentials"))
+			return
+		}
+
+		ctx := WithPrincipal(r.Context(), principal)
+		ctx = tenant.WithScope(ctx, scope)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequireScope rejects requests whose principal lacks the scope.
+func RequireScope(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := FromContext(r.Context())
+		if !ok {
+			apierror.Write(w, r, apierror.Unauthorized("Authentication required"))
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			apierror.Write(w, r, apierror.Forbidden("Missing required scope: "+string(scope)))
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// RequirePlatform rejects requests from principals that cannot manage tenants.
+func RequirePlatform(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := FromContext(r.Context())
+		if !ok {
+			apierror.Write(w, r, apierror.Unauthorized("Authentication required"))
+			return
+		}
+
+		if !principal.Platform {
+			apierror.Write(w, r, apierror.Forbidden("Tenant management requires a platform credential"))
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+func credentialFromRequest(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); key != "" {
+		return strings.TrimSpace(key)
+	}
+
+	header := r.Header.Get("Authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Scope string
+
+const (
+	ScopeRead    Scope = "read"
+	ScopeWrite   Scope = "write"
+	ScopeAnalyze Scope = "analyze"
+	// ScopeAdmin grants every other scope as well as key management.
+	ScopeAdmin Scope = "admin"
+)
+
+var validScopes = map[Scope]bool{
+	ScopeRead:    true,
+	ScopeWrite:   true,
+	ScopeAnalyze: true,
+	ScopeAdmin:   true,
+}
+
+// ScopeList is stored as a JSONB array of scopes.
+type ScopeList []Scope
+
+func (l ScopeList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+func (l *ScopeList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("unsupported type for ScopeList: %T", value)
+	}
+}
+
+func (l ScopeList) Has(scope Scope) bool {
+	for _, s := range l {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey is a long-lived credential. Only a SHA-256 hash of the key is
+// stored; the plaintext is shown once when the key is created.
+type APIKey struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Hash       string     `json:"-"`
+	Scopes     ScopeList  `gorm:"type:jsonb" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) (err error) {
+	k.ID = uuid.New()
+	return
+}
+
+func (k *APIKey) Usable(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/tenant"
+)
+
+const (
+	MethodAPIKey    = "api_key"
+	MethodJWT       = "jwt"
+	MethodBootstrap = "bootstrap"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	Subject  string
+	Method   string
+	KeyID    *uuid.UUID
+	TenantID string
+	Scopes   ScopeList
+	// Platform principals may manage tenants and act across them.
+	Platform bool
+}
+
+func (p *Principal) HasScope(scope Scope) bool {
+	return p != nil && p.Scopes.Has(scope)
+}
+
+// TenantScope returns the data scope of the principal. End users signed in
+// with a JWT only see their own documents unless they are tenant admins;
+// API keys see everything in their tenant. The system scope is reserved for
+// background jobs, so principals without a valid tenant ID get
+// ErrInvalidTenant instead.
+func (p *Principal) TenantScope() (tenant.Scope, error) {
+	scope := tenant.Scope{TenantID: p.TenantID}
+	if !validTenantID.MatchString(p.TenantID) {
+		return tenant.Scope{}, ErrInvalidTenant
+	}
+	if p.Method == MethodJWT && !p.Scopes.Has(ScopeAdmin) {
+		scope.OwnerID = p.Subject
+	}
+	return scope, nil
+}
+
+type contextKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok && p != nil
+}
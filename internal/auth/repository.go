@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// lastUsedResolution limits how often last_used_at is written for a busy key.
+const lastUsedResolution = time.Minute
+
+// Keys are looked up by hash before the tenant is known, so api_keys is not
+// covered by row-level security; List and Revoke filter by tenant explicitly.
+type Repository interface {
+	Create(key *APIKey) error
+	FindByHash(hash string) (*APIKey, error)
+	List(tenantID string) ([]APIKey, error)
+	Revoke(tenantID string, id uuid.UUID, at time.Time) error
+	TouchLastUsed(id uuid.UUID, at time.Time) error
+	IsNotFoundError(err error) bool
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(key *APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *repository) FindByHash(hash string) (*APIKey, error) {
+	var key APIKey
+	err := r.db.First(&key, "hash = ?", hash).Error
+	return &key, err
+}
+
+func (r *repository) List(tenantID string) ([]APIKey, error) {
+	var keys []APIKey
+	err := r.db.Where("tenant_id = ?", tenantID).Order("created_at").Find(&keys).Error
+	return keys, err
+}
+
+func (r *repository) Revoke(tenantID string, id uuid.UUID, at time.Time) error {
+	result := r.db.Model(&APIKey{}).
+		Where("id = ? AND tenant_id = ? AND revoked_at IS NULL", id, tenantID).
+		Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) TouchLastUsed(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, at.Add(-lastUsedResolution)).
+		UpdateColumn("last_used_at", at).Error
+}
+
+func (r *repository) IsNotFoundError(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"github.com/gorilla/mux"
+)
+
+func RegisterRoutes(r *mux.Router, h *Handler) {
+	r.HandleFunc("/admin/api-keys", RequireScope(ScopeAdmin, h.CreateKey)).Methods("POST")
+	r.HandleFunc("/admin/api-keys", RequireScope(ScopeAdmin, h.ListKeys)).Methods("GET")
+	r.HandleFunc("/admin/api-keys/{id}", RequireScope(ScopeAdmin, h.RevokeKey)).Methods("DELETE")
+
+	r.HandleFunc("/admin/tenants", RequirePlatform(h.CreateTenant)).Methods("POST")
+	r.HandleFunc("/admin/tenants", RequirePlatform(h.ListTenants)).Methods("GET")
+	r.HandleFunc("/admin/tenants/{id}/limits", RequirePlatform(h.UpdateTenantLimits)).Methods("PUT")
+	r.HandleFunc("/admin/tenants/{id}/pii-policy", RequirePlatform(h.UpdateTenantPIIPolicy)).Methods("PUT")
+	r.HandleFunc("/admin/tenants/{id}/upload-policy", RequirePlatform(h.UpdateTenantUploadPolicy)).Methods("PUT")
+}
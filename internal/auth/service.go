@@ -0,0 +1,289 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/internal/upload"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+var validTenantID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// KeyPrefix marks DocAI API keys so they can be told apart from JWTs.
+const KeyPrefix = "docai_"
+
+var (
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	ErrInvalidScopes   = errors.New("scopes must be one or more of: read, write, analyze, admin")
+	ErrInvalidKeyName  = errors.New("name is required")
+	ErrUnknownTenant   = errors.New("tenant does not exist")
+	ErrInvalidTenant   = errors.New("tenant id must be 1-64 lowercase letters, digits or dashes")
+	ErrTenantExists    = errors.New("tenant already exists")
+	ErrInvalidLimits   = errors.New("limits must not be negative")
+)
+
+type Config struct {
+	JWT JWTConfig
+	// BootstrapKey, when set, is accepted as a platform admin API key for the
+	// default tenant so that the first tenants and keys can be created. It is
+	// never stored.
+	BootstrapKey string
+	// Quotas, when set, has its cached limits dropped when a tenant's limits
+	// change, so this server applies them at once.
+	Quotas *quota.Service
+}
+
+type Service struct {
+	repo         Repository
+	tenants      tenant.Repository
+	jwt          *jwtVerifier
+	bootstrapKey string
+	quotas       *quota.Service
+}
+
+func NewService(repo Repository, tenants tenant.Repository, cfg Config) (*Service, error) {
+	verifier, err := newJWTVerifier(cfg.JWT)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		repo:         repo,
+		tenants:      tenants,
+		jwt:          verifier,
+		bootstrapKey: cfg.BootstrapKey,
+		quotas:       cfg.Quotas,
+	}, nil
+}
+
+// CreateKey generates a new API key for a tenant. The plaintext key is
+// returned only from this call.
+func (s *Service) CreateKey(ctx context.Context, tenantID, name string, scopes []Scope, expiresAt *time.Time) (*APIKey, string, error) {
+	if _, err := s.tenants.FindByID(tenantID); err != nil {
+		if s.tenants.IsNotFoundError(err) {
+			return nil, "", ErrUnknownTenant
+		}
+		return nil, "", err
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, "", ErrInvalidKeyName
+	}
+	if len(scopes) == 0 {
+		return nil, "", ErrInvalidScopes
+	}
+	for _, scope := range scopes {
+		if !validScopes[scope] {
+			return nil, "", fmt.Errorf("%w (got %q)", ErrInvalidScopes, scope)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	raw := KeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &APIKey{
+		TenantID:  tenantID,
+		Name:      name,
+		Prefix:    raw[:len(KeyPrefix)+6],
+		Hash:      hashKey(raw),
+		Scopes:    ScopeList(scopes),
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.repo.Create(key); err != nil {
+		logger.Error("Failed to create API key", logger.WithError(err))
+		return nil, "", err
+	}
+
+	logger.Info("API key created", logger.Fields{"key_id": key.ID, "tenant_id": key.TenantID, "name": key.Name, "scopes": key.Scopes})
+	return key, raw, nil
+}
+
+func (s *Service) ListKeys(ctx context.Context, tenantID string) ([]APIKey, error) {
+	return s.repo.List(tenantID)
+}
+
+func (s *Service) RevokeKey(ctx context.Context, tenantID string, id uuid.UUID) error {
+	if err := s.repo.Revoke(tenantID, id, time.Now()); err != nil {
+		return err
+	}
+	logger.Info("API key revoked", logger.Fields{"key_id": id})
+	return nil
+}
+
+func (s *Service) CreateTenant(ctx context.Context, id, name string, limits tenant.Limits) (*tenant.Tenant, error) {
+	if !validTenantID.MatchString(id) {
+		return nil, ErrInvalidTenant
+	}
+	if strings.TrimSpace(name) == "" {
+		name = id
+	}
+
+	if _, err := s.tenants.FindByID(id); err == nil {
+		return nil, ErrTenantExists
+	} else if !s.tenants.IsNotFoundError(err) {
+		return nil, err
+	}
+
+	if err := validateLimits(limits); err != nil {
+		return nil, err
+	}
+
+	t := &tenant.Tenant{ID: id, Name: name, Limits: limits}
+	if err := s.tenants.Create(t); err != nil {
+		logger.Error("Failed to create tenant", logger.WithError(err))
+		return nil, err
+	}
+
+	logger.Info("Tenant created", logger.Fields{"tenant_id": t.ID})
+	return t, nil
+}
+
+func (s *Service) ListTenants(ctx context.Context) ([]tenant.Tenant, error) {
+	return s.tenants.List()
+}
+
+// UpdateTenantLimits replaces a tenant's quota overrides. This server applies
+// them at once; other running servers pick up the change within a minute.
+func (s *Service) UpdateTenantLimits(ctx context.Context, id string, limits tenant.Limits) (*tenant.Tenant, error) {
+	if err := validateLimits(limits); err != nil {
+		return nil, err
+	}
+	if err := s.tenants.UpdateLimits(id, limits); err != nil {
+		return nil, err
+	}
+	if s.quotas != nil {
+		s.quotas.Invalidate(id)
+	}
+
+	logger.Info("Tenant limits updated", logger.Fields{"tenant_id": id})
+	return s.tenants.FindByID(id)
+}
+
+// UpdateTenantPIIPolicy sets the policy used when a tenant's documents are
+// sent to the LLM; nil reverts the tenant to the server default.
+func (s *Service) UpdateTenantPIIPolicy(ctx context.Context, id string, policy *pii.Policy) (*tenant.Tenant, error) {
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.tenants.UpdatePIIPolicy(id, policy); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Tenant PII policy updated", logger.Fields{"tenant_id": id})
+	return s.tenants.FindByID(id)
+}
+
+// UpdateTenantUploadPolicy sets the limits on what a tenant may upload; nil
+// reverts the tenant to the server default.
+func (s *Service) UpdateTenantUploadPolicy(ctx context.Context, id string, policy *upload.Policy) (*tenant.Tenant, error) {
+	if policy != nil {
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.tenants.UpdateUploadPolicy(id, policy); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Tenant upload policy updated", logger.Fields{"tenant_id": id})
+	return s.tenants.FindByID(id)
+}
+
+func (s *Service) IsTenantNotFoundError(err error) bool {
+	return s.tenants.IsNotFoundError(err)
+}
+
+func validateLimits(l tenant.Limits) error {
+	if (l.RequestsPerMinute != nil && *l.RequestsPerMinute < 0) ||
+		(l.DailyAnalyses != nil && *l.DailyAnalyses < 0) ||
+		(l.StorageBytes != nil && *l.StorageBytes < 0) ||
+		(l.MonthlyTokens != nil && *l.MonthlyTokens < 0) {
+		return ErrInvalidLimits
+	}
+	return nil
+}
+
+func (s *Service) IsNotFoundError(err error) bool {
+	return s.repo.IsNotFoundError(err)
+}
+
+// Authenticate resolves a raw credential, either an API key or a JWT, to a
+// principal.
+func (s *Service) Authenticate(ctx context.Context, credential string) (*Principal, error) {
+	if s.bootstrapKey != "" && subtle.ConstantTimeCompare([]byte(credential), []byte(s.bootstrapKey)) == 1 {
+		return &Principal{
+			Subject:  "bootstrap",
+			Method:   MethodBootstrap,
+			TenantID: tenant.DefaultTenant,
+			Scopes:   ScopeList{ScopeAdmin},
+			Platform: true,
+		}, nil
+	}
+
+	if strings.HasPrefix(credential, KeyPrefix) {
+		return s.authenticateKey(ctx, credential)
+	}
+
+	if s.jwt == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	principal, err := s.jwt.Verify(credential)
+	if err != nil {
+		logger.Debug("JWT rejected", logger.WithError(err))
+		return nil, ErrUnauthenticated
+	}
+	return principal, nil
+}
+
+func (s *Service) authenticateKey(ctx context.Context, raw string) (*Principal, error) {
+	key, err := s.repo.FindByHash(hashKey(raw))
+	if err != nil {
+		if !s.repo.IsNotFoundError(err) {
+			logger.Error("Failed to look up API key", logger.WithError(err))
+		}
+		return nil, ErrUnauthenticated
+	}
+
+	now := time.Now()
+	if !key.Usable(now) {
+		return nil, ErrUnauthenticated
+	}
+
+	if err := s.repo.TouchLastUsed(key.ID, now); err != nil {
+		logger.Warn("Failed to record API key usage", logger.Merge(logger.Fields{"key_id": key.ID}, logger.WithError(err)))
+	}
+
+	keyID := key.ID
+	return &Principal{
+		Subject:  "key:" + key.ID.String(),
+		Method:   MethodAPIKey,
+		KeyID:    &keyID,
+		TenantID: key.TenantID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+func hashKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
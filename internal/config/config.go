@@ -1,24 +1,168 @@
 package config
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/zjoart/docai/internal/upload"
+	"gopkg.in/yaml.v3"
 )
 
+// Config is layered from, in increasing precedence: the defaults below, a
+// YAML config file, environment variables and command-line flags. Every
+// field has a yaml key, an environment variable and a flag named after the
+// yaml key with dashes (database_url is --database-url). Fields tagged secret
+// are redacted when printed.
 type Config struct {
-	AppEnv           string
-	Port             string
-	DBURL            string
-	MinioEndpoint    string
-	MinioAccessKey   string
-	MinioSecretKey   string
-	MinioBucket      string
-	OpenRouterAPIKey string
+	AppEnv           string  `yaml:"app_env" env:"APP_ENV"`
+	Port             string  `yaml:"port" env:"PORT"`
+	GRPCPort         string  `yaml:"grpc_port" env:"GRPC_PORT"`
+	DBURL            string  `yaml:"database_url" env:"DATABASE_URL" secret:"true"`
+	MinioEndpoint    string  `yaml:"minio_endpoint" env:"MINIO_ENDPOINT"`
+	MinioAccessKey   string  `yaml:"minio_access_key" env:"MINIO_ACCESS_KEY"`
+	MinioSecretKey   string  `yaml:"minio_secret_key" env:"MINIO_SECRET_KEY" secret:"true"`
+	MinioBucket      string  `yaml:"minio_bucket" env:"MINIO_BUCKET"`
+	OpenRouterAPIKey string  `yaml:"openrouter_api_key" env:"OPENROUTER_API_KEY" secret:"true"`
+	LLMModel         string  `yaml:"llm_model" env:"LLM_MODEL"`
+	BatchWorkers     int     `yaml:"batch_workers" env:"BATCH_WORKERS"`
+	LLMRateLimit     float64 `yaml:"llm_rate_limit" env:"LLM_RATE_LIMIT"`
+
+	// AutoMigrate applies pending migrations on start instead of refusing to
+	// run against an outdated schema.
+	AutoMigrate bool `yaml:"auto_migrate" env:"AUTO_MIGRATE"`
+
+	WebhookMaxAttempts int           `yaml:"webhook_max_attempts" env:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookTimeout     time.Duration `yaml:"webhook_timeout" env:"WEBHOOK_TIMEOUT"`
+	// WebhookAllowPrivate lets webhooks target loopback and private
+	// addresses, for local development only.
+	WebhookAllowPrivate bool `yaml:"webhook_allow_private" env:"WEBHOOK_ALLOW_PRIVATE"`
+
+	AdminAPIKey string `yaml:"admin_api_key" env:"ADMIN_API_KEY" secret:"true"`
+	JWTSecret   string `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+	JWTJWKSFile string `yaml:"jwt_jwks_file" env:"JWT_JWKS_FILE"`
+	JWTIssuer   string `yaml:"jwt_issuer" env:"JWT_ISSUER"`
+	JWTAudience string `yaml:"jwt_audience" env:"JWT_AUDIENCE"`
+
+	// Default per-tenant quotas; 0 disables a limit.
+	QuotaRequestsPerMinute int   `yaml:"quota_requests_per_minute" env:"QUOTA_REQUESTS_PER_MINUTE"`
+	QuotaDailyAnalyses     int64 `yaml:"quota_daily_analyses" env:"QUOTA_DAILY_ANALYSES"`
+	QuotaStorageBytes      int64 `yaml:"quota_storage_bytes" env:"QUOTA_STORAGE_BYTES"`
+	QuotaMonthlyTokens     int64 `yaml:"quota_monthly_tokens" env:"QUOTA_MONTHLY_TOKENS"`
+
+	// Default PII policy applied before text is sent to the LLM.
+	PIIMode  string   `yaml:"pii_mode" env:"PII_MODE"`
+	PIIKinds []string `yaml:"pii_kinds" env:"PII_KINDS"`
+
+	// Encryption at rest: a base64 256-bit master key and/or a keyfile
+	// holding several keys for rotation.
+	MasterKey     string `yaml:"master_key" env:"MASTER_KEY" secret:"true"`
+	MasterKeyFile string `yaml:"master_key_file" env:"MASTER_KEY_FILE"`
+
+	// Default upload policy; tenants may override it. Types are MIME types and
+	// a max_pages of 0 means no page limit. UploadMemoryBytes is how much of
+	// an upload is buffered in memory.
+	UploadMaxBytes       int64            `yaml:"upload_max_bytes" env:"UPLOAD_MAX_BYTES"`
+	UploadMaxBytesByType map[string]int64 `yaml:"upload_max_bytes_by_type" env:"UPLOAD_MAX_BYTES_BY_TYPE"`
+	UploadAllowedTypes   []string         `yaml:"upload_allowed_types" env:"UPLOAD_ALLOWED_TYPES"`
+	UploadMaxPages       int              `yaml:"upload_max_pages" env:"UPLOAD_MAX_PAGES"`
+	UploadMemoryBytes    int64            `yaml:"upload_memory_bytes" env:"UPLOAD_MEMORY_BYTES"`
+
+	// clamd address for malware scanning of uploads; empty disables it.
+	ClamdAddr   string        `yaml:"clamd_addr" env:"CLAMD_ADDR"`
+	ScanTimeout time.Duration `yaml:"scan_timeout" env:"SCAN_TIMEOUT"`
+
+	// How long Idempotency-Key responses are kept for replay, and how soon
+	// the key of a request whose server died may be taken over by a retry.
+	IdempotencyTTL         time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL"`
+	IdempotencyLockTimeout time.Duration `yaml:"idempotency_lock_timeout" env:"IDEMPOTENCY_LOCK_TIMEOUT"`
+
+	// Trace exporter: none, otlp or stdout. The OTLP endpoint comes from the
+	// standard OTEL_EXPORTER_OTLP_* variables.
+	TracesExporter string `yaml:"otel_traces_exporter" env:"OTEL_TRACES_EXPORTER"`
+	ServiceName    string `yaml:"otel_service_name" env:"OTEL_SERVICE_NAME"`
+
+	// Readiness probing and how long /readyz fails before the server stops
+	// accepting connections on shutdown.
+	ReadyTimeout       time.Duration `yaml:"ready_timeout" env:"READY_TIMEOUT"`
+	ReadyCheckLLM      bool          `yaml:"ready_check_llm" env:"READY_CHECK_LLM"`
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+
+	// HTTP server timeouts, and how long shutdown waits for in-flight
+	// requests and background analyses before requeueing what is left.
+	ReadTimeout     time.Duration `yaml:"http_read_timeout" env:"HTTP_READ_TIMEOUT"`
+	WriteTimeout    time.Duration `yaml:"http_write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout     time.Duration `yaml:"http_idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+}
+
+// Default returns the configuration used for anything not set elsewhere.
+func Default() *Config {
+	return &Config{
+		AppEnv:       "development",
+		Port:         "8080",
+		GRPCPort:     "50051",
+		LLMModel:     "gpt-4o-mini",
+		BatchWorkers: 4,
+		LLMRateLimit: 2,
+
+		WebhookMaxAttempts: 8,
+		WebhookTimeout:     10 * time.Second,
+
+		QuotaRequestsPerMinute: 600,
+
+		PIIMode: "redact",
+
+		UploadMaxBytes:     upload.Default().MaxBytes,
+		UploadAllowedTypes: upload.Default().AllowedTypes,
+		UploadMemoryBytes:  10 << 20,
+
+		ScanTimeout: 30 * time.Second,
+
+		IdempotencyTTL:         24 * time.Hour,
+		IdempotencyLockTimeout: time.Minute,
+
+		TracesExporter: "none",
+		ServiceName:    "docai",
+
+		ReadyTimeout:       2 * time.Second,
+		ShutdownDrainDelay: 5 * time.Second,
+
+		ReadTimeout:     time.Minute,
+		WriteTimeout:    2 * time.Minute,
+		IdleTimeout:     2 * time.Minute,
+		ShutdownTimeout: 30 * time.Second,
+	}
 }
 
-func Load() (*Config, error) {
+// Load builds the configuration from the environment and args, which may
+// hold --config and any field flag. See LoadFlags.
+func Load(args ...string) (*Config, error) {
+	return LoadFlags(flag.NewFlagSet("docai", flag.ContinueOnError), args)
+}
+
+// LoadFlags registers --config and a flag per field on fs, parses args and
+// layers defaults, the config file (--config, or CONFIG_FILE), environment
+// variables and flags. Callers can define flags of their own on fs first.
+//
+// The configuration is returned even when it fails validation, so it can be
+// printed; the error then lists every problem found, not just the first.
+func LoadFlags(fs *flag.FlagSet, args []string) (*Config, error) {
+	cfg, err := Parse(fs, args)
+	if cfg == nil {
+		return nil, err
+	}
+	return cfg, errors.Join(err, cfg.Validate())
+}
+
+// Parse is LoadFlags without validation, for commands that only need part of
+// the configuration and check it themselves.
+func Parse(fs *flag.FlagSet, args []string) (*Config, error) {
 	// Try loading .env from current or parent directories
 	pathsToCheck := []string{".env", "../.env", "../../.env"}
 	for _, path := range pathsToCheck {
@@ -28,21 +172,74 @@ func Load() (*Config, error) {
 		}
 	}
 
-	return &Config{
-		AppEnv:           getEnv("APP_ENV"),
-		Port:             getEnv("PORT"),
-		DBURL:            getEnv("DATABASE_URL"),
-		MinioEndpoint:    getEnv("MINIO_ENDPOINT"),
-		MinioAccessKey:   getEnv("MINIO_ACCESS_KEY"),
-		MinioSecretKey:   getEnv("MINIO_SECRET_KEY"),
-		MinioBucket:      getEnv("MINIO_BUCKET"),
-		OpenRouterAPIKey: getEnv("OPENROUTER_API_KEY"),
-	}, nil
+	cfg := Default()
+	fields := cfg.fields()
+
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	overrides := make(map[string]string)
+	for _, f := range fields {
+		name := f.flagName()
+		usage := fmt.Sprintf("overrides %s (%s)", f.key, f.env)
+		set := func(value string) error {
+			overrides[name] = value
+			return nil
+		}
+		if f.value.Kind() == reflect.Bool {
+			fs.BoolFunc(name, usage, set)
+		} else {
+			fs.Func(name, usage, set)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.readFile(*configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, f := range fields {
+		if value := os.Getenv(f.env); value != "" {
+			if err := f.set(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.env, err))
+			}
+		}
+		if value, ok := overrides[f.flagName()]; ok {
+			if err := f.set(value); err != nil {
+				errs = append(errs, fmt.Errorf("--%s: %w", f.flagName(), err))
+			}
+		}
+	}
+
+	return cfg, errors.Join(errs...)
+}
+
+// readFile applies a YAML config file. Unknown keys are rejected so typos
+// don't go unnoticed.
+func (c *Config) readFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
 }
 
-func getEnv(key string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// UploadPolicy is the default upload policy.
+func (c *Config) UploadPolicy() upload.Policy {
+	return upload.Policy{
+		MaxBytes:       c.UploadMaxBytes,
+		MaxBytesByType: c.UploadMaxBytesByType,
+		AllowedTypes:   c.UploadAllowedTypes,
+		MaxPages:       c.UploadMaxPages,
 	}
-	panic(fmt.Sprintf("%s is required", key))
 }
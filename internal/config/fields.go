@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a settable Config field together with its tags.
+type field struct {
+	key    string
+	env    string
+	secret bool
+	value  reflect.Value
+}
+
+func (c *Config) fields() []field {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	fields := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i)
+		fields = append(fields, field{
+			key:    tag.Tag.Get("yaml"),
+			env:    tag.Tag.Get("env"),
+			secret: tag.Tag.Get("secret") == "true",
+			value:  v.Field(i),
+		})
+	}
+	return fields
+}
+
+func (f field) flagName() string {
+	return strings.ReplaceAll(f.key, "_", "-")
+}
+
+// set parses raw into the field. Lists are comma-separated and empty entries
+// are dropped; maps are comma-separated key=value pairs.
+func (f field) set(raw string) error {
+	v := f.value
+
+	if v.Type() == durationType {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.New("must be a duration (e.g. 10s)")
+		}
+		v.SetInt(int64(parsed))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+
+	case reflect.Int, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		v.SetInt(parsed)
+
+	case reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		v.SetFloat(parsed)
+
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("must be true or false")
+		}
+		v.SetBool(parsed)
+
+	case reflect.Map:
+		parsed := make(map[string]int64)
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(item, "=")
+			n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if !ok || err != nil {
+				return errors.New("must be a list of key=integer pairs")
+			}
+			parsed[strings.TrimSpace(key)] = n
+		}
+		v.Set(reflect.ValueOf(parsed))
+
+	case reflect.Slice:
+		var list []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		v.Set(reflect.ValueOf(list))
+
+	default:
+		return errors.New("unsupported field type " + v.Type().String())
+	}
+	return nil
+}
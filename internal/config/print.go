@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[redacted]"
+
+// Print writes the configuration as a YAML config file, with secrets
+// redacted. Durations are written the way they are read, e.g. 30s.
+func (c *Config) Print(w io.Writer) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, f := range c.fields() {
+		value := f.value.Interface()
+		if f.secret {
+			value = redact(f.value.String())
+		} else if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		} else if f.value.Kind() == reflect.Slice && f.value.Len() == 0 {
+			value = []string{}
+		} else if f.value.Kind() == reflect.Map && f.value.Len() == 0 {
+			value = map[string]int64{}
+		}
+
+		var node yaml.Node
+		if err := node.Encode(value); err != nil {
+			return fmt.Errorf("failed to encode %s: %w", f.key, err)
+		}
+		doc.Content = append(doc.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.key}, &node)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// redact hides a secret but shows whether it is set. URLs keep everything
+// but their password, so the host being connected to stays visible.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	if u, err := url.Parse(secret); err == nil && u.Scheme != "" && u.Host != "" {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			return u.Redacted()
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zjoart/docai/internal/pii"
+)
+
+// Validate checks the configuration and reports every problem at once.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	required := map[string]string{
+		"database_url":       c.DBURL,
+		"minio_endpoint":     c.MinioEndpoint,
+		"minio_access_key":   c.MinioAccessKey,
+		"minio_secret_key":   c.MinioSecretKey,
+		"minio_bucket":       c.MinioBucket,
+		"openrouter_api_key": c.OpenRouterAPIKey,
+	}
+	for _, f := range c.fields() {
+		if value, ok := required[f.key]; ok {
+			check(value != "", f.key, "is required (set %s)", f.env)
+		}
+	}
+
+	port, err := strconv.Atoi(c.Port)
+	check(err == nil && port > 0 && port < 65536, "port", "must be a TCP port, got %q", c.Port)
+	grpcPort, err := strconv.Atoi(c.GRPCPort)
+	check(err == nil && grpcPort > 0 && grpcPort < 65536, "grpc_port", "must be a TCP port, got %q", c.GRPCPort)
+	check(c.GRPCPort != c.Port, "grpc_port", "must differ from port")
+	check(c.LLMModel != "", "llm_model", "must not be empty")
+	check(c.BatchWorkers >= 1, "batch_workers", "must be at least 1")
+	check(c.LLMRateLimit >= 0, "llm_rate_limit", "must not be negative")
+	check(c.WebhookMaxAttempts >= 1, "webhook_max_attempts", "must be at least 1")
+
+	check(c.QuotaRequestsPerMinute >= 0, "quota_requests_per_minute", "must not be negative")
+	check(c.QuotaDailyAnalyses >= 0, "quota_daily_analyses", "must not be negative")
+	check(c.QuotaStorageBytes >= 0, "quota_storage_bytes", "must not be negative")
+	check(c.QuotaMonthlyTokens >= 0, "quota_monthly_tokens", "must not be negative")
+
+	policy := pii.Policy{Mode: pii.Mode(c.PIIMode)}
+	for _, kind := range c.PIIKinds {
+		policy.Kinds = append(policy.Kinds, pii.Kind(kind))
+	}
+	check(policy.Validate() == nil, "pii_mode", "unknown mode %q or kinds %v", c.PIIMode, c.PIIKinds)
+
+	uploadErr := c.UploadPolicy().Validate()
+	check(uploadErr == nil, "upload_*", "%v", uploadErr)
+	check(c.UploadMemoryBytes > 0, "upload_memory_bytes", "must be positive")
+
+	switch c.TracesExporter {
+	case "none", "otlp", "stdout":
+	default:
+		check(false, "otel_traces_exporter", "must be none, otlp or stdout, got %q", c.TracesExporter)
+	}
+
+	positive := map[string]time.Duration{
+		"webhook_timeout":          c.WebhookTimeout,
+		"scan_timeout":             c.ScanTimeout,
+		"idempotency_ttl":          c.IdempotencyTTL,
+		"idempotency_lock_timeout": c.IdempotencyLockTimeout,
+		"ready_timeout":            c.ReadyTimeout,
+		"http_read_timeout":        c.ReadTimeout,
+		"http_write_timeout":       c.WriteTimeout,
+		"http_idle_timeout":        c.IdleTimeout,
+		"shutdown_timeout":         c.ShutdownTimeout,
+	}
+	for _, f := range c.fields() {
+		if d, ok := positive[f.key]; ok {
+			check(d > 0, f.key, "must be a positive duration")
+		}
+	}
+	check(c.ShutdownDrainDelay >= 0, "shutdown_drain_delay", "must not be negative")
+
+	return errors.Join(errs...)
+}
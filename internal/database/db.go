@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"log"
 
 	"gorm.io/driver/postgres"
@@ -16,3 +17,12 @@ func Connect(dbURL string) (*gorm.DB, error) {
 	log.Println("Connected to Database")
 	return db, nil
 }
+
+// Ping checks that the database accepts connections.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -4,33 +4,91 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/zjoart/docai/internal/tracing"
+	"github.com/zjoart/docai/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type AnalysisResult struct {
 	Summary  string                 `json:"summary"`
 	Type     string                 `json:"type"`
 	Metadata map[string]interface{} `json:"metadata"`
+	// TokensUsed is the prompt and completion tokens billed for the call.
+	TokensUsed int `json:"-"`
 }
 
+// Observer receives the outcome of every LLM call.
+type Observer interface {
+	ObserveLLMRequest(model string, duration time.Duration, tokens int, err error)
+}
+
+type nopObserver struct{}
+
+func (nopObserver) ObserveLLMRequest(string, time.Duration, int, error) {}
+
 type Analyzer struct {
-	client *openai.Client
-	model  string
+	client   *openai.Client
+	model    string
+	observer Observer
 }
 
-func NewAnalyzer(apiKey string) *Analyzer {
+// DefaultModel is used when no model is configured.
+const DefaultModel = "gpt-4o-mini"
+
+// NewAnalyzer creates an analyzer backed by OpenRouter. model may be empty to
+// use DefaultModel and observer may be nil.
+func NewAnalyzer(apiKey, model string, observer Observer) *Analyzer {
+	if model == "" {
+		model = DefaultModel
+	}
+	if observer == nil {
+		observer = nopObserver{}
+	}
+
 	config := openai.DefaultConfig(apiKey)
 	config.BaseURL = "https://openrouter.ai/api/v1"
+	config.HTTPClient = &http.Client{Transport: requestIDTransport{base: tracing.Transport(http.DefaultTransport)}}
 
 	return &Analyzer{
-		client: openai.NewClientWithConfig(config),
-		model:  "gpt-4o-mini",
+		client:   openai.NewClientWithConfig(config),
+		model:    model,
+		observer: observer,
+	}
+}
+
+// Ping checks that the provider is reachable and accepts the API key. It
+// lists models, which bills no tokens.
+func (a *Analyzer) Ping(ctx context.Context) error {
+	_, err := a.client.ListModels(ctx)
+	return err
+}
+
+// requestIDTransport forwards the request ID of the call's context to the
+// LLM provider, so provider-side logs can be matched with ours.
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if id := logger.RequestIDFromContext(req.Context()); id != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("X-Request-ID", id)
 	}
+	return t.base.RoundTrip(req)
 }
 
-func (a *Analyzer) AnalyzeText(ctx context.Context, text string) (*AnalysisResult, error) {
+func (a *Analyzer) AnalyzeText(ctx context.Context, text string) (_ *AnalysisResult, err error) {
+	ctx, span := tracing.Start(ctx, "analyzer.AnalyzeText",
+		attribute.String("llm.model", a.model),
+		attribute.Int("llm.input_length", len(text)),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	if len(text) > 100000 {
 		text = text[:100000]
 	}
@@ -40,11 +98,14 @@ func (a *Analyzer) AnalyzeText(ctx context.Context, text string) (*AnalysisResul
 2. "type": The deduced document type (e.g., Invoice, CV, Report, Letter, Contract, Other).
 3. "metadata": A flat JSON object containing extracted key fields (e.g., date, author, total_amount, invoice_number).
 
+Values such as [EMAIL_1] or [IBAN_2] are placeholders for redacted personal data; copy them into the output exactly as written.
+
 Return ONLY the JSON.
 
 Document Text:
 %s`, text)
 
+	started := time.Now()
 	resp, err := a.client.CreateChatCompletion(
 		ctx,
 		openai.ChatCompletionRequest{
@@ -58,9 +119,18 @@ Document Text:
 		},
 	)
 
+	elapsed := time.Since(started)
 	if err != nil {
+		a.observer.ObserveLLMRequest(a.model, elapsed, 0, err)
 		return nil, err
 	}
+	a.observer.ObserveLLMRequest(a.model, elapsed, resp.Usage.TotalTokens, nil)
+	span.SetAttributes(attribute.Int("llm.tokens", resp.Usage.TotalTokens))
+	logger.FromContext(ctx).Info("LLM request completed", logger.Fields{
+		"model":      a.model,
+		"latency_ms": elapsed.Milliseconds(),
+		"tokens":     resp.Usage.TotalTokens,
+	})
 
 	content := resp.Choices[0].Message.Content
 	content = strings.TrimPrefix(content, "```json")
@@ -70,8 +140,9 @@ Document Text:
 
 	var result AnalysisResult
 	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse LLM response: %v, content: %s", err, content)
+		return &AnalysisResult{TokensUsed: resp.Usage.TotalTokens}, fmt.Errorf("failed to parse LLM response: %v, content: %s", err, content)
 	}
+	result.TokensUsed = resp.Usage.TotalTokens
 
 	return &result, nil
 }
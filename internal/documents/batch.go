@@ -0,0 +1,212 @@
+package documents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+	"golang.org/x/time/rate"
+)
+
+var ErrEmptyBatch = apierror.NotFound("No documents matched the batch request")
+
+// BatchFilter selects documents for a batch. Documents that are already
+// queued or processing are never matched by a filter.
+type BatchFilter struct {
+	Status  Status `json:"status,omitempty"`
+	DocType string `json:"doc_type,omitempty"`
+}
+
+type BatchRequest struct {
+	IDs    []uuid.UUID  `json:"ids,omitempty"`
+	Filter *BatchFilter `json:"filter,omitempty"`
+}
+
+type BatchConfig struct {
+	// Workers is the number of documents analyzed concurrently.
+	Workers int
+	// RatePerSecond caps how many LLM calls the batch makes per second.
+	RatePerSecond float64
+}
+
+// BatchRunner fans batch analyses out over a bounded worker pool, rate
+// limited against the LLM provider.
+type BatchRunner struct {
+	service *Service
+	repo    Repository
+	workers int
+	limiter *rate.Limiter
+	// pending counts documents of running batches not analyzed yet.
+	pending atomic.Int64
+}
+
+func NewBatchRunner(service *Service, repo Repository, cfg BatchConfig) *BatchRunner {
+	workers := cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	limit := rate.Limit(cfg.RatePerSecond)
+	if cfg.RatePerSecond <= 0 {
+		limit = rate.Inf
+	}
+
+	return &BatchRunner{
+		service: service,
+		repo:    repo,
+		workers: workers,
+		limiter: rate.NewLimiter(limit, 1),
+	}
+}
+
+// Start records a new batch and analyzes its documents in the background.
+// The returned batch can be polled with GetBatch.
+func (b *BatchRunner) Start(ctx context.Context, req BatchRequest) (*AnalysisBatch, error) {
+	if len(req.IDs) == 0 && req.Filter == nil {
+		return nil, ErrEmptyBatch
+	}
+
+	scope, ok := tenant.FromContext(ctx)
+	if !ok || scope.IsSystem() {
+		return nil, b.service.apiError(tenant.ErrNoScope)
+	}
+
+	ids, err := b.repo.FindIDs(ctx, uniqueIDs(req.IDs), req.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve batch documents: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return nil, ErrEmptyBatch
+	}
+
+	filter, _ := json.Marshal(req)
+	batch := &AnalysisBatch{
+		TenantID: scope.TenantID,
+		OwnerID:  scope.OwnerID,
+		Status:   "running",
+		Total:    len(ids),
+		Filter:   filter,
+	}
+
+	if err := b.repo.CreateBatch(ctx, batch, ids); err != nil {
+		logger.FromContext(ctx).Error("Failed to create analysis batch", logger.WithError(err))
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Info("Analysis batch started", logger.Fields{"batch_id": batch.ID, "total": batch.Total})
+
+	// the batch outlives the request, but must stay within its tenant
+	err = b.service.Go(audit.Detach(ctx), func(ctx context.Context) {
+		b.run(ctx, batch.ID, ids)
+	})
+	if err != nil {
+		b.finish(ctx, batch.ID, "interrupted")
+		return nil, err
+	}
+
+	return batch, nil
+}
+
+func (b *BatchRunner) GetBatch(ctx context.Context, id uuid.UUID) (*AnalysisBatch, error) {
+	batch, err := b.repo.FindBatchByID(ctx, id)
+	if b.repo.IsNotFoundError(err) {
+		return nil, apierror.Wrap(apierror.CodeNotFound, "Batch not found", err)
+	}
+	return batch, err
+}
+
+// QueueDepth is the number of documents waiting in running batches.
+func (b *BatchRunner) QueueDepth() int64 {
+	return b.pending.Load()
+}
+
+// run analyzes the batch's documents. If shutdown interrupts it, documents
+// not reached yet are left as they were, those being analyzed are requeued
+// and the batch ends as interrupted.
+func (b *BatchRunner) run(ctx context.Context, batchID uuid.UUID, ids []uuid.UUID) {
+	b.pending.Add(int64(len(ids)))
+	jobs := make(chan uuid.UUID)
+	var wg sync.WaitGroup
+
+	for i := 0; i < b.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for docID := range jobs {
+				err := b.analyze(ctx, docID)
+				b.pending.Add(-1)
+				if err != nil && b.service.interrupted(ctx) {
+					continue
+				}
+				if err != nil {
+					logger.FromContext(ctx).Warn("Batch analysis failed for document", logger.Merge(logger.Fields{"batch_id": batchID, "id": docID}, logger.WithError(err)))
+				}
+				if recErr := b.repo.RecordBatchResult(ctx, batchID, docID, err); recErr != nil {
+					logger.FromContext(ctx).Error("Failed to record batch result", logger.Merge(logger.Fields{"batch_id": batchID, "id": docID}, logger.WithError(recErr)))
+				}
+			}
+		}()
+	}
+
+	dispatched := 0
+dispatch:
+	for _, docID := range ids {
+		select {
+		case jobs <- docID:
+			dispatched++
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	b.pending.Add(-int64(len(ids) - dispatched))
+
+	if ctx.Err() != nil {
+		b.finish(context.WithoutCancel(ctx), batchID, "interrupted")
+		logger.FromContext(ctx).Warn("Analysis batch interrupted by shutdown", logger.Fields{"batch_id": batchID, "dispatched": dispatched, "total": len(ids)})
+		return
+	}
+
+	if b.finish(ctx, batchID, "completed") {
+		logger.FromContext(ctx).Info("Analysis batch completed", logger.Fields{"batch_id": batchID})
+	}
+}
+
+func (b *BatchRunner) finish(ctx context.Context, batchID uuid.UUID, status string) bool {
+	if err := b.repo.FinishBatch(ctx, batchID, status); err != nil {
+		logger.FromContext(ctx).Error("Failed to finish batch", logger.Merge(logger.Fields{"batch_id": batchID, "status": status}, logger.WithError(err)))
+		return false
+	}
+	return true
+}
+
+func (b *BatchRunner) analyze(ctx context.Context, id uuid.UUID) error {
+	if err := b.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	_, err := b.service.AnalyzeDocument(ctx, id)
+	return err
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, docID := range ids {
+		if _, ok := seen[docID]; ok {
+			continue
+		}
+		seen[docID] = struct{}{}
+		unique = append(unique, docID)
+	}
+	return unique
+}
@@ -0,0 +1,76 @@
+package documents
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zjoart/docai/internal/envelope"
+)
+
+// Columns encrypted with the document's data key. Each value is bound to its
+// row and column so ciphertext can't be moved between them.
+const (
+	columnExtractedText = "extracted_text"
+	columnSummary       = "summary"
+	columnMetadata      = "metadata"
+)
+
+func sealAAD(doc *Document, column string) string {
+	return doc.ID.String() + ":" + column
+}
+
+// seal returns a copy of doc with its sensitive columns encrypted, ready to
+// be written. doc itself keeps the plaintext.
+func (r *repository) seal(doc *Document) (*Document, error) {
+	dek, err := r.keys.Unwrap(doc.DataKey)
+	if err != nil || dek == nil {
+		return doc, err
+	}
+
+	sealed := *doc
+	if sealed.ExtractedText, err = envelope.SealString(dek, doc.ExtractedText, sealAAD(doc, columnExtractedText)); err != nil {
+		return nil, err
+	}
+	if sealed.Summary, err = envelope.SealString(dek, doc.Summary, sealAAD(doc, columnSummary)); err != nil {
+		return nil, err
+	}
+
+	// metadata lives in a jsonb column, so the ciphertext is stored as a
+	// JSON string
+	if len(doc.Metadata) > 0 {
+		value, err := envelope.SealString(dek, string(doc.Metadata), sealAAD(doc, columnMetadata))
+		if err != nil {
+			return nil, err
+		}
+		if sealed.Metadata, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	return &sealed, nil
+}
+
+// open decrypts the sensitive columns of a document read from the database
+// in place. Rows written before encryption was enabled pass through.
+func (r *repository) open(doc *Document) error {
+	dek, err := r.keys.Unwrap(doc.DataKey)
+	if err != nil {
+		return fmt.Errorf("failed to unwrap data key of document %s: %w", doc.ID, err)
+	}
+
+	if doc.ExtractedText, err = envelope.OpenString(dek, doc.ExtractedText, sealAAD(doc, columnExtractedText)); err != nil {
+		return err
+	}
+	if doc.Summary, err = envelope.OpenString(dek, doc.Summary, sealAAD(doc, columnSummary)); err != nil {
+		return err
+	}
+
+	var value string
+	if json.Unmarshal(doc.Metadata, &value) == nil && envelope.IsSealed(value) {
+		plaintext, err := envelope.OpenString(dek, value, sealAAD(doc, columnMetadata))
+		if err != nil {
+			return err
+		}
+		doc.Metadata = json.RawMessage(plaintext)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package documents
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/scanner"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/internal/upload"
+)
+
+// apiError gives the errors of the repository and of the packages the
+// service relies on their API type. Errors that already have one, or that
+// are not meant for clients, are returned unchanged.
+func (s *Service) apiError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := apierror.As(err); ok {
+		return err
+	}
+
+	var tooLarge *upload.TooLargeError
+	switch exceeded, isQuota := quota.AsExceeded(err); {
+	case isQuota:
+		return exceeded.APIError()
+	case s.IsNotFoundError(err), errors.Is(err, tenant.ErrOutOfScope):
+		// documents of other tenants don't exist as far as the caller knows
+		return apierror.Wrap(apierror.CodeNotFound, "Document not found", err)
+	case errors.Is(err, tenant.ErrNoScope):
+		return apierror.Wrap(apierror.CodeForbidden, "No tenant scope", err)
+	case errors.As(err, &tooLarge):
+		return apierror.Wrap(apierror.CodeTooLarge, fmt.Sprintf("File too large (max %d bytes)", tooLarge.Limit), err)
+	case errors.Is(err, upload.ErrTypeNotAllowed):
+		return apierror.Wrap(apierror.CodeUnsupported, "File type not allowed for this tenant", err)
+	case errors.Is(err, scanner.ErrUnavailable):
+		return apierror.Wrap(apierror.CodeUnavailable, "Malware scanner unavailable, try again later", err)
+	}
+	return err
+}
@@ -0,0 +1,146 @@
+package extractor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Formats Extract understands, named after their file extensions.
+const (
+	FormatPDF  = "pdf"
+	FormatDOCX = "docx"
+	FormatText = "txt"
+)
+
+// unprintableWarning is the share of unprintable characters on a page above
+// which the text is probably garbage, e.g. from an unsupported font encoding.
+const unprintableWarning = 0.1
+
+var ErrUnsupportedFormat = errors.New("unsupported document format")
+
+// Page describes the text taken from one page. DOCX and plain text files are
+// treated as a single page.
+type Page struct {
+	Number      int `json:"number"`
+	Chars       int `json:"chars"`
+	Words       int `json:"words"`
+	Lines       int `json:"lines"`
+	Unprintable int `json:"unprintable"`
+}
+
+// Result is the outcome of an extraction. Warnings point at text that was
+// skipped or looks wrong without failing the whole document.
+type Result struct {
+	Format   string   `json:"format"`
+	Encoding string   `json:"encoding"`
+	Text     string   `json:"text"`
+	Pages    []Page   `json:"pages"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// FormatOf returns the format of a file from its extension.
+func FormatOf(filename string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
+
+// Extract pulls the text out of a document of the given format. It is the
+// extraction used for uploads, so it can be run on its own to debug a file.
+func Extract(format string, reader io.ReaderAt, size int64, progress ProgressFunc) (*Result, error) {
+	if progress == nil {
+		progress = func(int, int) {}
+	}
+	result := &Result{Format: format}
+
+	switch format {
+	case FormatPDF:
+		if err := extractPDF(reader, size, progress, result); err != nil {
+			return nil, err
+		}
+
+	case FormatDOCX:
+		text, err := ExtractTextFromDOCX(reader, size)
+		if err != nil {
+			return nil, err
+		}
+		result.Text = text
+		result.Pages = []Page{pageStats(1, text)}
+		progress(1, 1)
+
+	case FormatText:
+		raw, err := io.ReadAll(io.NewSectionReader(reader, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		result.Text = string(raw)
+		result.Encoding = detectEncoding(raw)
+		if !strings.HasPrefix(result.Encoding, "utf-8") {
+			result.warn("text is %s, not UTF-8, and is kept as is", result.Encoding)
+		}
+		result.Pages = []Page{pageStats(1, result.Text)}
+		progress(1, 1)
+
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+
+	if result.Encoding == "" {
+		result.Encoding = detectEncoding([]byte(result.Text))
+	}
+	for _, page := range result.Pages {
+		if page.Chars > 0 && float64(page.Unprintable)/float64(page.Chars) > unprintableWarning {
+			result.warn("page %d: %d of %d characters are unprintable; the font encoding may be unsupported", page.Number, page.Unprintable, page.Chars)
+		}
+	}
+	if strings.TrimSpace(result.Text) == "" {
+		result.warn("no text was extracted; the document would be rejected")
+	}
+	return result, nil
+}
+
+func pageStats(number int, text string) Page {
+	page := Page{
+		Number: number,
+		Chars:  utf8.RuneCountInString(text),
+		Words:  len(strings.Fields(text)),
+	}
+	if text != "" {
+		page.Lines = strings.Count(strings.TrimRight(text, "\n"), "\n") + 1
+	}
+	for _, r := range text {
+		if r == utf8.RuneError || unicode.Is(unicode.Co, r) || (unicode.IsControl(r) && !unicode.IsSpace(r)) {
+			page.Unprintable++
+		}
+	}
+	return page
+}
+
+// detectEncoding recognizes UTF-8 and the byte order marks of UTF-16 and
+// UTF-32. Anything else that is not valid UTF-8 is reported as unknown.
+func detectEncoding(b []byte) string {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8 (bom)"
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE, 0x00, 0x00}):
+		return "utf-32le"
+	case bytes.HasPrefix(b, []byte{0x00, 0x00, 0xFE, 0xFF}):
+		return "utf-32be"
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+		return "utf-16le"
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		return "utf-16be"
+	case utf8.Valid(b):
+		return "utf-8"
+	default:
+		return "unknown"
+	}
+}
+
+func (r *Result) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
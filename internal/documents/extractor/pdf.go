@@ -3,35 +3,72 @@ package extractor
 import (
 	"bytes"
 	"io"
+	"strings"
 
 	"github.com/ledongthuc/pdf"
-	"github.com/zjoart/docai/pkg/logger"
 )
 
-func ExtractTextFromPDF(reader io.ReaderAt, size int64) (string, error) {
+// ProgressFunc is called as extraction moves through a document, e.g. once
+// per page of a PDF.
+type ProgressFunc func(current, total int)
+
+// CountPDFPages reads only a PDF's page tree, so limits can be checked
+// before any text is extracted.
+func CountPDFPages(reader io.ReaderAt, size int64) (int, error) {
 	r, err := pdf.NewReader(reader, size)
 	if err != nil {
+		return 0, err
+	}
+	return r.NumPage(), nil
+}
+
+func ExtractTextFromPDF(reader io.ReaderAt, size int64, progress ProgressFunc) (string, error) {
+	if progress == nil {
+		progress = func(int, int) {}
+	}
+	result := &Result{Format: FormatPDF}
+	if err := extractPDF(reader, size, progress, result); err != nil {
 		return "", err
 	}
+	return result.Text, nil
+}
 
-	var buf bytes.Buffer
+// extractPDF is ExtractTextFromPDF with per-page stats. Pages that fail are
+// skipped with a warning rather than failing the document.
+func extractPDF(reader io.ReaderAt, size int64, progress ProgressFunc, result *Result) error {
+	r, err := pdf.NewReader(reader, size)
+	if err != nil {
+		return err
+	}
 
+	var buf bytes.Buffer
 	totalPages := r.NumPage()
-
 	for pageIndex := 1; pageIndex <= totalPages; pageIndex++ {
+		progress(pageIndex, totalPages)
+
 		p := r.Page(pageIndex)
 		if p.V.IsNull() {
+			result.warn("page %d: missing from the page tree", pageIndex)
+			result.Pages = append(result.Pages, Page{Number: pageIndex})
 			continue
 		}
 
 		text, err := p.GetPlainText(nil)
 		if err != nil {
-			logger.Error("Error extracting text from PDF", logger.WithError(err))
+			result.warn("page %d: %v", pageIndex, err)
+			result.Pages = append(result.Pages, Page{Number: pageIndex})
 			continue
 		}
+
+		page := pageStats(pageIndex, text)
+		if strings.TrimSpace(text) == "" {
+			result.warn("page %d: no text, it may be a scanned image", pageIndex)
+		}
+		result.Pages = append(result.Pages, page)
 		buf.WriteString(text)
 		buf.WriteString("\n")
 	}
 
-	return buf.String(), nil
+	result.Text = buf.String()
+	return nil
 }
@@ -0,0 +1,517 @@
+package documents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/auth"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/internal/upload"
+	docaiv1 "github.com/zjoart/docai/pkg/api/docai/v1"
+	"github.com/zjoart/docai/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// downloadChunkSize is how much of a file each DownloadDocument message
+// carries.
+const downloadChunkSize = 64 << 10
+
+// GRPCServer serves docai.v1.DocumentService. It is the gRPC counterpart of
+// Handler and shares its Service, scopes, quotas and audit trail.
+type GRPCServer struct {
+	docaiv1.UnimplementedDocumentServiceServer
+
+	service *Service
+	batches *BatchRunner
+	// closing ends open watch streams, which would otherwise hold up a
+	// graceful stop forever.
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewGRPCServer(service *Service, batches *BatchRunner) *GRPCServer {
+	return &GRPCServer{service: service, batches: batches, closing: make(chan struct{})}
+}
+
+// CloseStreams ends every open watch stream; call it before
+// grpc.Server.GracefulStop.
+func (g *GRPCServer) CloseStreams() {
+	g.closeOnce.Do(func() { close(g.closing) })
+}
+
+func (g *GRPCServer) UploadDocument(stream docaiv1.DocumentService_UploadDocumentServer) error {
+	ctx := stream.Context()
+	if err := requireScope(ctx, auth.ScopeWrite); err != nil {
+		return err
+	}
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	meta := first.GetMetadata()
+	if meta == nil || meta.GetFilename() == "" {
+		return g.error(ctx, apierror.BadRequest("The first message must carry the file's metadata"))
+	}
+
+	policy := g.service.UploadPolicy(ctx)
+	mimeType, ok := upload.TypeOf(meta.GetFilename())
+	if !ok || !policy.Allows(mimeType) {
+		return g.error(ctx, apierror.Unsupported("File type not supported. Allowed types: "+strings.Join(policy.AllowedExtensions(), ", ")))
+	}
+	if max := policy.MaxBytesFor(mimeType); meta.GetSizeBytes() > max {
+		return g.error(ctx, apierror.TooLarge(fmt.Sprintf("File too large (max %d bytes)", max)))
+	}
+
+	doc, err := g.service.UploadDocument(ctx, meta.GetFilename(), &uploadReader{stream: stream}, meta.GetSizeBytes(), meta.GetContentType())
+	if err != nil {
+		return g.error(ctx, err)
+	}
+
+	message := "Document uploaded successfully"
+	if meta.GetProcessImmediately() {
+		if queued, started, err := g.service.StartAnalysis(ctx, doc); err != nil {
+			logger.FromContext(ctx).Error("Failed to queue document for analysis", logger.WithError(err))
+		} else {
+			doc = queued
+			message = "Document uploaded and analysis started"
+			if !started {
+				message = "Document uploaded and queued for analysis"
+			}
+		}
+	}
+
+	return stream.SendAndClose(&docaiv1.UploadDocumentResponse{Message: message, Document: toProtoDocument(doc)})
+}
+
+func (g *GRPCServer) GetDocument(ctx context.Context, req *docaiv1.GetDocumentRequest) (*docaiv1.Document, error) {
+	docID, err := g.documentID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = requireScope(ctx, auth.ScopeRead)
+	var doc *Document
+	if err == nil {
+		if doc, err = g.service.GetDocument(ctx, docID); err != nil {
+			err = g.error(ctx, err)
+		}
+	}
+	g.record(ctx, audit.ActionView, docID, err)
+	if err != nil {
+		return nil, err
+	}
+	return toProtoDocument(doc), nil
+}
+
+func (g *GRPCServer) ListDocuments(ctx context.Context, req *docaiv1.ListDocumentsRequest) (*docaiv1.ListDocumentsResponse, error) {
+	if err := requireScope(ctx, auth.ScopeRead); err != nil {
+		return nil, err
+	}
+
+	filter := ListFilter{Status: fromProtoStatus(req.GetStatus()), DocType: req.GetDocType(), Limit: defaultListLimit, Offset: int(req.GetOffset())}
+	if req.GetLimit() != 0 {
+		filter.Limit = int(req.GetLimit())
+	}
+	if filter.Limit < 1 || filter.Limit > maxListLimit {
+		return nil, g.error(ctx, apierror.BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxListLimit)))
+	}
+	if filter.Offset < 0 {
+		return nil, g.error(ctx, apierror.BadRequest("offset must be a non-negative number"))
+	}
+
+	list, err := g.service.ListDocuments(ctx, filter)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to list documents", logger.WithError(err))
+		return nil, g.error(ctx, apierror.Internal("Failed to list documents"))
+	}
+
+	resp := &docaiv1.ListDocumentsResponse{Documents: make([]*docaiv1.Document, len(list))}
+	for i := range list {
+		resp.Documents[i] = toProtoDocument(&list[i])
+	}
+	return resp, nil
+}
+
+func (g *GRPCServer) DownloadDocument(req *docaiv1.DownloadDocumentRequest, stream docaiv1.DocumentService_DownloadDocumentServer) error {
+	ctx := stream.Context()
+	docID, err := g.documentID(ctx, req.GetId())
+	if err != nil {
+		return err
+	}
+
+	err = requireScope(ctx, auth.ScopeRead)
+	var (
+		doc     *Document
+		content io.ReadCloser
+	)
+	if err == nil {
+		if doc, content, err = g.service.OpenFile(ctx, docID); err != nil {
+			err = g.error(ctx, err)
+		}
+	}
+	g.record(ctx, audit.ActionDownload, docID, err)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	contentType := doc.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	resp := &docaiv1.DownloadDocumentResponse{Filename: doc.Filename, ContentType: contentType}
+	buf := make([]byte, downloadChunkSize)
+	for {
+		n, readErr := content.Read(buf)
+		if n > 0 {
+			resp.Chunk = buf[:n]
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			resp = &docaiv1.DownloadDocumentResponse{}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			logger.FromContext(ctx).Warn("Download interrupted", logger.Merge(logger.Fields{"id": docID}, logger.WithError(readErr)))
+			return g.error(ctx, apierror.Internal("Download interrupted"))
+		}
+	}
+	// an empty file still tells the client its name and type
+	if resp.Filename != "" {
+		return stream.Send(resp)
+	}
+	return nil
+}
+
+func (g *GRPCServer) AnalyzeDocument(ctx context.Context, req *docaiv1.AnalyzeDocumentRequest) (*docaiv1.Document, error) {
+	if err := requireScope(ctx, auth.ScopeAnalyze); err != nil {
+		return nil, err
+	}
+	docID, err := g.documentID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := g.service.AnalyzeDocument(ctx, docID)
+	if err != nil {
+		return nil, g.error(ctx, err)
+	}
+	return toProtoDocument(doc), nil
+}
+
+func (g *GRPCServer) AnalyzeBatch(ctx context.Context, req *docaiv1.AnalyzeBatchRequest) (*docaiv1.AnalysisBatch, error) {
+	if err := requireScope(ctx, auth.ScopeAnalyze); err != nil {
+		return nil, err
+	}
+
+	var batchReq BatchRequest
+	for _, raw := range req.GetIds() {
+		docID, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, g.error(ctx, apierror.BadRequest(fmt.Sprintf("Invalid file ID format: %q", raw)))
+		}
+		batchReq.IDs = append(batchReq.IDs, docID)
+	}
+	if f := req.GetFilter(); f != nil {
+		batchReq.Filter = &BatchFilter{Status: fromProtoStatus(f.GetStatus()), DocType: f.GetDocType()}
+	}
+	if len(batchReq.IDs) == 0 && batchReq.Filter == nil {
+		return nil, g.error(ctx, apierror.BadRequest("Either ids or filter is required"))
+	}
+
+	batch, err := g.batches.Start(ctx, batchReq)
+	if err != nil {
+		return nil, g.error(ctx, err)
+	}
+	return toProtoBatch(batch), nil
+}
+
+func (g *GRPCServer) GetBatch(ctx context.Context, req *docaiv1.GetBatchRequest) (*docaiv1.AnalysisBatch, error) {
+	if err := requireScope(ctx, auth.ScopeRead); err != nil {
+		return nil, err
+	}
+	batchID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, g.error(ctx, apierror.BadRequest("Invalid batch ID format"))
+	}
+
+	batch, err := g.batches.GetBatch(ctx, batchID)
+	if err != nil {
+		return nil, g.error(ctx, err)
+	}
+	return toProtoBatch(batch), nil
+}
+
+// WatchDocument is the gRPC form of StreamDocumentEvents: the current status
+// is sent first so clients don't miss a transition that happened before they
+// connected.
+func (g *GRPCServer) WatchDocument(req *docaiv1.WatchDocumentRequest, stream docaiv1.DocumentService_WatchDocumentServer) error {
+	ctx := stream.Context()
+	docID, err := g.documentID(ctx, req.GetId())
+	if err != nil {
+		return err
+	}
+
+	ch, unsubscribe := g.service.events.Subscribe(events.ForDocument(docID), sseBufferSize)
+	defer unsubscribe()
+
+	err = requireScope(ctx, auth.ScopeRead)
+	var doc *Document
+	if err == nil {
+		if doc, err = g.service.GetDocument(ctx, docID); err != nil {
+			err = g.error(ctx, err)
+		}
+	}
+	g.record(ctx, audit.ActionView, docID, err)
+	if err != nil {
+		return err
+	}
+
+	initial := toProtoEvent(events.Event{
+		Type:       events.StatusChanged,
+		DocumentID: doc.ID,
+		TenantID:   doc.TenantID,
+		OwnerID:    doc.OwnerID,
+		Status:     string(doc.Status),
+		Timestamp:  doc.UpdatedAt,
+	})
+	if err := stream.Send(initial); err != nil {
+		return err
+	}
+	return g.watch(stream, ch)
+}
+
+func (g *GRPCServer) WatchEvents(_ *docaiv1.WatchEventsRequest, stream docaiv1.DocumentService_WatchEventsServer) error {
+	ctx := stream.Context()
+	if err := requireScope(ctx, auth.ScopeRead); err != nil {
+		return err
+	}
+	scope, ok := tenant.FromContext(ctx)
+	if !ok {
+		return g.error(ctx, apierror.Forbidden("No tenant scope"))
+	}
+
+	ch, unsubscribe := g.service.events.Subscribe(func(e events.Event) bool {
+		return scope.Allows(e.TenantID, e.OwnerID)
+	}, sseBufferSize)
+	defer unsubscribe()
+
+	return g.watch(stream, ch)
+}
+
+func (g *GRPCServer) watch(stream grpc.ServerStreamingServer[docaiv1.DocumentEvent], ch <-chan events.Event) error {
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+
+		case <-g.closing:
+			return apierror.GRPCError(stream.Context(), apierror.Unavailable("Server is shutting down"))
+
+		case e, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(toProtoEvent(e)); err != nil {
+				logger.FromContext(stream.Context()).Debug("Event stream closed", logger.WithError(err))
+				return err
+			}
+		}
+	}
+}
+
+// documentID parses a document ID from a request.
+func (g *GRPCServer) documentID(ctx context.Context, raw string) (uuid.UUID, error) {
+	docID, err := uuid.Parse(raw)
+	if err != nil {
+		logger.FromContext(ctx).Error("Invalid file ID format", logger.Fields{"id": raw})
+		return uuid.Nil, g.error(ctx, apierror.BadRequest("Invalid file ID format"))
+	}
+	return docID, nil
+}
+
+// record audits a view or download, the way audit.Service.Track does for
+// the REST routes.
+func (g *GRPCServer) record(ctx context.Context, action audit.Action, docID uuid.UUID, err error) {
+	event := audit.Event{Action: action, Outcome: audit.OutcomeSuccess, DocumentID: &docID}
+	if err != nil {
+		code := status.Code(err)
+		event.Outcome = audit.OutcomeFailure
+		if code == codes.Unauthenticated || code == codes.PermissionDenied || code == codes.NotFound {
+			event.Outcome = audit.OutcomeDenied
+		}
+		event.Detail = code.String()
+	}
+	g.service.audit.Record(ctx, event)
+}
+
+// error converts a service error to a gRPC status through the same codes
+// the REST handlers respond with.
+func (g *GRPCServer) error(ctx context.Context, err error) error {
+	return apierror.GRPCError(ctx, err)
+}
+
+// requireScope is auth.RequireScope for gRPC calls.
+func requireScope(ctx context.Context, scope auth.Scope) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return apierror.GRPCError(ctx, apierror.Unauthorized("Authentication required"))
+	}
+	if !principal.HasScope(scope) {
+		return apierror.GRPCError(ctx, apierror.Forbidden("Missing required scope: "+string(scope)))
+	}
+	return nil
+}
+
+// uploadReader reads the file content from the chunks of an upload stream.
+type uploadReader struct {
+	stream docaiv1.DocumentService_UploadDocumentServer
+	buf    []byte
+}
+
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetMetadata() != nil {
+			return 0, apierror.BadRequest("Metadata may only be sent in the first message")
+		}
+		r.buf = req.GetChunk()
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+var protoStatuses = map[Status]docaiv1.DocumentStatus{
+	StatusUploaded:   docaiv1.DocumentStatus_DOCUMENT_STATUS_UPLOADED,
+	StatusQueued:     docaiv1.DocumentStatus_DOCUMENT_STATUS_QUEUED,
+	StatusProcessing: docaiv1.DocumentStatus_DOCUMENT_STATUS_PROCESSING,
+	StatusAnalyzed:   docaiv1.DocumentStatus_DOCUMENT_STATUS_ANALYZED,
+	StatusFailed:     docaiv1.DocumentStatus_DOCUMENT_STATUS_FAILED,
+	StatusRejected:   docaiv1.DocumentStatus_DOCUMENT_STATUS_REJECTED,
+}
+
+// toProtoStatus converts a status to its gRPC enum; unknown statuses are
+// DOCUMENT_STATUS_UNSPECIFIED.
+func toProtoStatus(s Status) docaiv1.DocumentStatus {
+	return protoStatuses[s]
+}
+
+func fromProtoStatus(s docaiv1.DocumentStatus) Status {
+	for status, proto := range protoStatuses {
+		if proto == s {
+			return status
+		}
+	}
+	return ""
+}
+
+func toProtoDocument(doc *Document) *docaiv1.Document {
+	if doc == nil {
+		return nil
+	}
+	return &docaiv1.Document{
+		Id:            doc.ID.String(),
+		TenantId:      doc.TenantID,
+		OwnerId:       doc.OwnerID,
+		Filename:      doc.Filename,
+		FileUrl:       doc.FileUrl,
+		ContentType:   doc.ContentType,
+		SizeBytes:     doc.SizeBytes,
+		ExtractedText: doc.ExtractedText,
+		Summary:       doc.Summary,
+		DocType:       doc.DocType,
+		Metadata:      toStruct(doc.Metadata),
+		Status:        toProtoStatus(doc.Status),
+		FailureReason: doc.FailureReason,
+		Attempts:      int32(doc.Attempts),
+		Version:       int32(doc.Version),
+		CreatedAt:     timestamppb.New(doc.CreatedAt),
+		UpdatedAt:     timestamppb.New(doc.UpdatedAt),
+	}
+}
+
+func toProtoBatch(b *AnalysisBatch) *docaiv1.AnalysisBatch {
+	batch := &docaiv1.AnalysisBatch{
+		Id:        b.ID.String(),
+		TenantId:  b.TenantID,
+		OwnerId:   b.OwnerID,
+		Status:    b.Status,
+		Total:     int32(b.Total),
+		Succeeded: int32(b.Succeeded),
+		Failed:    int32(b.Failed),
+		CreatedAt: timestamppb.New(b.CreatedAt),
+		UpdatedAt: timestamppb.New(b.UpdatedAt),
+	}
+	if b.CompletedAt != nil {
+		batch.CompletedAt = timestamppb.New(*b.CompletedAt)
+	}
+	for _, item := range b.Failures {
+		batch.Failures = append(batch.Failures, &docaiv1.AnalysisBatchItem{
+			DocumentId: item.DocumentID.String(),
+			Status:     item.Status,
+			Error:      item.Error,
+			UpdatedAt:  timestamppb.New(item.UpdatedAt),
+		})
+	}
+	return batch
+}
+
+func toProtoEvent(e events.Event) *docaiv1.DocumentEvent {
+	event := &docaiv1.DocumentEvent{
+		Id:             e.ID,
+		Type:           string(e.Type),
+		DocumentId:     e.DocumentID.String(),
+		TenantId:       e.TenantID,
+		OwnerId:        e.OwnerID,
+		Status:         toProtoStatus(Status(e.Status)),
+		PreviousStatus: toProtoStatus(Status(e.PreviousStatus)),
+		Error:          e.Error,
+		Timestamp:      timestamppb.New(e.Timestamp),
+	}
+	if e.Progress != nil {
+		event.Progress = &docaiv1.Progress{Stage: e.Progress.Stage, Current: int32(e.Progress.Current), Total: int32(e.Progress.Total)}
+	}
+	if e.Data != nil {
+		if data, err := json.Marshal(e.Data); err == nil {
+			event.Data = toStruct(data)
+		}
+	}
+	return event
+}
+
+// toStruct converts a JSON object to a Struct. Anything else, including
+// invalid JSON, gives nil.
+func toStruct(raw json.RawMessage) *structpb.Struct {
+	if len(raw) == 0 {
+		return nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return nil
+	}
+	s, err := structpb.NewStruct(fields)
+	if err != nil {
+		logger.Debug("Failed to convert JSON to a Struct", logger.WithError(err))
+		return nil
+	}
+	return s
+}
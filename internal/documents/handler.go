@@ -1,18 +1,53 @@
 package documents
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
-	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/idempotency"
+	"github.com/zjoart/docai/internal/upload"
 	"github.com/zjoart/docai/pkg/id"
 	"github.com/zjoart/docai/pkg/logger"
 )
 
+// multipartOverhead allows for the form fields and part headers around the
+// file in an upload request.
+const multipartOverhead = 1 << 20
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+	// maxJSONBody bounds the body of analyze requests.
+	maxJSONBody = 1 << 20
+)
+
+// HandlerConfig tunes request handling; zero values use the defaults.
+type HandlerConfig struct {
+	// MultipartMemory is how much of an upload is buffered in memory before
+	// the rest spills to a temporary file. Defaults to 10MB.
+	MultipartMemory int64
+	// Idempotency replays uploads and analyses retried with the same
+	// Idempotency-Key; nil disables it.
+	Idempotency *idempotency.Service
+}
+
 type Handler struct {
 	service *Service
+	batches *BatchRunner
+	config  HandlerConfig
+	// closing ends open event streams, which would otherwise hold up a
+	// graceful shutdown forever.
+	closing   chan struct{}
+	closeOnce sync.Once
 }
 
 func writeJSON(w http.ResponseWriter, status int, v interface{}) {
@@ -21,43 +56,77 @@ func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	json.NewEncoder(w).Encode(v)
 }
 
-func writeErrorJSON(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"message": message})
+func NewHandler(service *Service, batches *BatchRunner, config HandlerConfig) *Handler {
+	if config.MultipartMemory <= 0 {
+		config.MultipartMemory = 10 << 20
+	}
+	return &Handler{service: service, batches: batches, config: config, closing: make(chan struct{})}
+}
+
+// CloseStreams ends every open event stream; register it with
+// http.Server.RegisterOnShutdown.
+func (h *Handler) CloseStreams() {
+	h.closeOnce.Do(func() { close(h.closing) })
+}
+
+// uploadLimit is the largest upload request body the caller's policy allows.
+func (h *Handler) uploadLimit(r *http.Request) int64 {
+	return h.service.UploadPolicy(r.Context()).Limit() + multipartOverhead
 }
 
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func jsonLimit(*http.Request) int64 {
+	return maxJSONBody
 }
 
 func (h *Handler) UploadDocument(w http.ResponseWriter, r *http.Request) {
+	policy := h.service.UploadPolicy(r.Context())
 
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		writeErrorJSON(w, http.StatusBadRequest, "Failed to parse form")
+	// refuse oversized bodies up front and stop reading the moment one goes
+	// over, rather than after the whole form has been parsed
+	limit := h.uploadLimit(r)
+	if r.ContentLength > limit {
+		apierror.Write(w, r, apierror.TooLarge(fmt.Sprintf("File too large (max %d bytes)", policy.Limit())))
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+
+	if err := r.ParseMultipartForm(h.config.MultipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			apierror.Write(w, r, apierror.TooLarge(fmt.Sprintf("File too large (max %d bytes)", policy.Limit())))
+			return
+		}
+		apierror.Write(w, r, apierror.BadRequest("Failed to parse form"))
 		return
 	}
 
 	file, header, err := r.FormFile("file")
 	if err != nil {
-		writeErrorJSON(w, http.StatusBadRequest, "File is required")
+		apierror.Write(w, r, apierror.BadRequest("File is required"))
 		return
 	}
 
 	defer file.Close()
 
-	ext := filepath.Ext(header.Filename)
-	if ext != ".pdf" && ext != ".txt" && ext != ".docx" {
-		writeErrorJSON(w, http.StatusBadRequest, "File type not supported. Allowed types: .pdf, .docx, .txt")
+	mimeType, ok := upload.TypeOf(header.Filename)
+	if !ok || !policy.Allows(mimeType) {
+		apierror.Write(w, r, apierror.Unsupported("File type not supported. Allowed types: "+strings.Join(policy.AllowedExtensions(), ", ")))
 		return
 	}
 
-	if header.Size > 5*1024*1024 {
-		writeErrorJSON(w, http.StatusBadRequest, "File too large (max 5MB)")
+	if max := policy.MaxBytesFor(mimeType); header.Size > max {
+		apierror.Write(w, r, apierror.TooLarge(fmt.Sprintf("File too large (max %d bytes)", max)))
 		return
 	}
 
 	doc, err := h.service.UploadDocument(r.Context(), header.Filename, file, header.Size, header.Header.Get("Content-Type"))
 	if err != nil {
-		writeErrorJSON(w, http.StatusInternalServerError, err.Error())
+		problem := apierror.NewProblem(r, err)
+		if errors.Is(err, ErrMalwareDetected) {
+			// the rejected document is kept, so the client can refer to it
+			problem.Extensions["document"] = doc
+		}
+		apierror.WriteProblem(w, problem)
 		return
 	}
 
@@ -65,20 +134,15 @@ func (h *Handler) UploadDocument(w http.ResponseWriter, r *http.Request) {
 	message := "Document uploaded successfully"
 
 	if processImmediately {
-
-		if err := h.service.UpdateStatus(r.Context(), doc.ID, "processing"); err != nil {
-			logger.Error("Failed to update status to processing", logger.WithError(err))
-
+		if queued, started, err := h.service.StartAnalysis(r.Context(), doc); err != nil {
+			logger.FromContext(r.Context()).Error("Failed to queue document for analysis", logger.WithError(err))
 		} else {
-			doc.Status = "processing"
+			doc = queued
 			message = "Document uploaded and analysis started"
-
-			go func() {
-
-				if _, err := h.service.AnalyzeDocument(context.Background(), doc.ID); err != nil {
-					logger.Error("Background analysis failed", logger.WithError(err))
-				}
-			}()
+			if !started {
+				// left queued, the analysis resumes on the next start
+				message = "Document uploaded and queued for analysis"
+			}
 		}
 	}
 
@@ -93,46 +157,140 @@ func (h *Handler) AnalyzeDocument(w http.ResponseWriter, r *http.Request) {
 
 	id, err := id.IsValidUUID(vars["id"])
 	if err != nil {
-		logger.Error("Invalid file ID format", logger.Fields{"id": id})
-		writeErrorJSON(w, http.StatusBadRequest, "Invalid file ID format")
+		logger.FromContext(r.Context()).Error("Invalid file ID format", logger.Fields{"id": id})
+		apierror.Write(w, r, apierror.BadRequest("Invalid file ID format"))
 		return
 	}
 
-	currentDoc, err := h.service.GetDocument(r.Context(), id)
+	doc, err := h.service.AnalyzeDocument(r.Context(), id)
 	if err != nil {
-		writeErrorJSON(w, http.StatusNotFound, "Document not found")
+		apierror.Write(w, r, err)
 		return
 	}
 
-	if currentDoc.Status == "processing" {
-		writeErrorJSON(w, http.StatusConflict, "Document is already being processed")
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (h *Handler) GetDocument(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := id.IsValidUUID(vars["id"])
+	if err != nil {
+		logger.FromContext(r.Context()).Error("Invalid file ID format", logger.Fields{"id": id})
+		apierror.Write(w, r, apierror.BadRequest("Invalid file ID format"))
 		return
 	}
 
-	doc, err := h.service.AnalyzeDocument(r.Context(), id)
+	doc, err := h.service.GetDocument(r.Context(), id)
 	if err != nil {
-		writeErrorJSON(w, http.StatusInternalServerError, err.Error())
+		apierror.Write(w, r, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, doc)
 }
 
-func (h *Handler) GetDocument(w http.ResponseWriter, r *http.Request) {
+// ListDocuments returns the caller's documents, newest first, optionally
+// narrowed by status and doc_type and paged with limit and offset.
+func (h *Handler) ListDocuments(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := ListFilter{Status: Status(q.Get("status")), DocType: q.Get("doc_type"), Limit: defaultListLimit}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		apierror.Write(w, r, apierror.BadRequest("Invalid status"))
+		return
+	}
+	if raw := q.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			apierror.Write(w, r, apierror.BadRequest(fmt.Sprintf("limit must be between 1 and %d", maxListLimit)))
+			return
+		}
+		filter.Limit = limit
+	}
+	if raw := q.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			apierror.Write(w, r, apierror.BadRequest("offset must be a non-negative number"))
+			return
+		}
+		filter.Offset = offset
+	}
+
+	list, err := h.service.ListDocuments(r.Context(), filter)
+	if err != nil {
+		logger.FromContext(r.Context()).Error("Failed to list documents", logger.WithError(err))
+		apierror.Write(w, r, apierror.Internal("Failed to list documents"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// DownloadDocument streams the decrypted original file.
+func (h *Handler) DownloadDocument(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 
 	id, err := id.IsValidUUID(vars["id"])
 	if err != nil {
-		logger.Error("Invalid file ID format", logger.Fields{"id": id})
-		writeErrorJSON(w, http.StatusBadRequest, "Invalid file ID format")
+		apierror.Write(w, r, apierror.BadRequest("Invalid file ID format"))
 		return
 	}
 
-	doc, err := h.service.GetDocument(r.Context(), id)
+	doc, content, err := h.service.OpenFile(r.Context(), id)
 	if err != nil {
-		writeErrorJSON(w, http.StatusInternalServerError, err.Error())
+		apierror.Write(w, r, err)
 		return
 	}
+	defer content.Close()
 
-	writeJSON(w, http.StatusOK, doc)
+	contentType := doc.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": doc.Filename}))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		logger.FromContext(r.Context()).Warn("Download interrupted", logger.Merge(logger.Fields{"id": id}, logger.WithError(err)))
+	}
+}
+
+func (h *Handler) AnalyzeBatch(w http.ResponseWriter, r *http.Request) {
+	var req BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	if len(req.IDs) == 0 && req.Filter == nil {
+		apierror.Write(w, r, apierror.BadRequest("Either ids or filter is required"))
+		return
+	}
+
+	batch, err := h.batches.Start(r.Context(), req)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, batch)
+}
+
+func (h *Handler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	batchID, err := id.IsValidUUID(vars["id"])
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid batch ID format"))
+		return
+	}
+
+	batch, err := h.batches.GetBatch(r.Context(), batchID)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, batch)
 }
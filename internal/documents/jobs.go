@@ -0,0 +1,147 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+// ErrShuttingDown is returned when background work is refused because the
+// service is shutting down.
+var ErrShuttingDown = apierror.Unavailable("Service is shutting down")
+
+// jobs tracks background work started by the service so shutdown can wait
+// for it and, once its deadline passes, interrupt it.
+type jobs struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	stopping bool
+	// halt is cancelled when shutdown stops waiting; every job's context is
+	// cancelled with it.
+	halt   context.Context
+	cancel context.CancelFunc
+}
+
+func newJobs() *jobs {
+	halt, cancel := context.WithCancel(context.Background())
+	return &jobs{halt: halt, cancel: cancel}
+}
+
+// Go runs fn in the background as a tracked job. Its context is cancelled if
+// Shutdown gives up waiting for it. ErrShuttingDown is returned once Shutdown
+// has been called.
+func (s *Service) Go(ctx context.Context, fn func(ctx context.Context)) error {
+	s.jobs.mu.Lock()
+	defer s.jobs.mu.Unlock()
+
+	if s.jobs.stopping {
+		return ErrShuttingDown
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(s.jobs.halt, cancel)
+
+	s.jobs.wg.Add(1)
+	go func() {
+		defer s.jobs.wg.Done()
+		defer cancel()
+		defer stop()
+		fn(ctx)
+	}()
+	return nil
+}
+
+// StartAnalysis queues doc and analyzes it in a background job. started is
+// false when the job could not be started because the service is shutting
+// down; the document is left queued and resumes on the next start.
+func (s *Service) StartAnalysis(ctx context.Context, doc *Document) (queued *Document, started bool, err error) {
+	queued, err = s.UpdateStatus(ctx, doc.ID, StatusQueued)
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = s.Go(audit.Detach(ctx), func(ctx context.Context) {
+		if _, err := s.AnalyzeDocument(ctx, queued.ID); err != nil && !s.interrupted(ctx) {
+			logger.FromContext(ctx).Error("Background analysis failed", logger.WithError(err))
+		}
+	})
+	return queued, err == nil, nil
+}
+
+// Shutdown refuses new background jobs and waits for running ones until ctx
+// is done. Jobs still running then are interrupted: the documents they were
+// analyzing go back to queued and ctx's error is returned once they have.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.jobs.mu.Lock()
+	s.jobs.stopping = true
+	s.jobs.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.jobs.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.jobs.cancel()
+	<-done
+	return ctx.Err()
+}
+
+// ResumeQueued analyzes, one at a time in a background job, every document
+// left queued, such as those handed back by a previous Shutdown. Documents
+// another instance claims first are skipped.
+func (s *Service) ResumeQueued(ctx context.Context) error {
+	ctx = tenant.WithScope(ctx, tenant.System)
+
+	ids, err := s.repo.FindIDsByStatus(ctx, StatusQueued)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	logger.FromContext(ctx).Info("Resuming queued analyses", logger.Fields{"count": len(ids)})
+
+	return s.Go(ctx, func(ctx context.Context) {
+		for _, id := range ids {
+			if ctx.Err() != nil {
+				return
+			}
+			_, err := s.AnalyzeDocument(ctx, id)
+			if err != nil && !errors.Is(err, ErrAlreadyProcessing) && !s.interrupted(ctx) {
+				logger.FromContext(ctx).Error("Resumed analysis failed", logger.Merge(logger.Fields{"id": id}, logger.WithError(err)))
+			}
+		}
+	})
+}
+
+// interrupted reports whether ctx was cancelled because Shutdown stopped
+// waiting for background jobs.
+func (s *Service) interrupted(ctx context.Context) bool {
+	return ctx.Err() != nil && s.jobs.halt.Err() != nil
+}
+
+// requeue hands a claimed document back to the queue after its analysis was
+// interrupted, releasing the analysis it reserved against the quota.
+func (s *Service) requeue(ctx context.Context, doc *Document) {
+	ctx = context.WithoutCancel(ctx)
+	s.quotas.ReleaseAnalysis(ctx, doc.TenantID)
+
+	if err := s.transition(ctx, doc, StatusQueued, ""); err != nil {
+		logger.FromContext(ctx).Error("Failed to requeue interrupted analysis", logger.WithError(err))
+		return
+	}
+	logger.FromContext(ctx).Info("Interrupted analysis requeued")
+}
@@ -0,0 +1,15 @@
+package documents
+
+import "time"
+
+// Metrics receives measurements from the document pipeline. The server plugs
+// in a Prometheus implementation; the pipeline itself knows nothing of it.
+type Metrics interface {
+	ObserveUpload(format string, sizeBytes int64)
+	ObserveExtraction(format string, duration time.Duration, err error)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) ObserveUpload(string, int64)                    {}
+func (nopMetrics) ObserveExtraction(string, time.Duration, error) {}
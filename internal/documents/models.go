@@ -9,21 +9,84 @@ import (
 )
 
 type Document struct {
-	ID            uuid.UUID       `gorm:"type:uuid;primary_key;" json:"id"`
-	Filename      string          `json:"filename"`
-	FileUrl       string          `json:"file_url"`
-	ContentType   string          `json:"content_type"`
-	StoragePath   string          `json:"-"`
-	ExtractedText string          `json:"extracted_text"`
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	TenantID    string    `json:"tenant_id"`
+	OwnerID     string    `json:"owner_id,omitempty"`
+	Filename    string    `json:"filename"`
+	FileUrl     string    `json:"file_url,omitempty"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	StoragePath string    `json:"-"`
+	// DataKey is the document's data key, wrapped by a master key. It
+	// encrypts the stored file and the sensitive columns.
+	DataKey       string          `json:"-"`
+	ExtractedText string          `json:"extracted_text,omitempty"`
 	Summary       string          `json:"summary"`
 	DocType       string          `json:"doc_type"`
 	Metadata      json.RawMessage `gorm:"type:jsonb" json:"metadata"`
-	Status        string          `json:"status"` // uploaded, processed, analyzed
+	Status        Status          `json:"status"`
+	FailureReason string          `json:"failure_reason,omitempty"`
+	Attempts      int             `json:"attempts"`
+	Version       int             `gorm:"default:1" json:"version"`
 	CreatedAt     time.Time       `json:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
 }
 
 func (d *Document) BeforeCreate(tx *gorm.DB) (err error) {
-	d.ID = uuid.New()
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
 	return
 }
+
+// AfterFind points FileUrl at the download endpoint, replacing the storage
+// URLs older rows hold: objects are encrypted and the bucket is private.
+// Rejected documents cannot be downloaded, so they have none.
+func (d *Document) AfterFind(tx *gorm.DB) (err error) {
+	d.FileUrl = ""
+	if d.Status != StatusRejected && d.StoragePath != "" {
+		d.FileUrl = downloadPath(d.ID)
+	}
+	return
+}
+
+func downloadPath(id uuid.UUID) string {
+	return "/documents/" + id.String() + "/download"
+}
+
+// ListFilter narrows GET /documents. Documents are listed newest first.
+type ListFilter struct {
+	Status  Status
+	DocType string
+	Limit   int
+	Offset  int
+}
+
+// AnalysisBatch tracks a bulk re-analysis started through POST /documents/analyze.
+type AnalysisBatch struct {
+	ID          uuid.UUID           `gorm:"type:uuid;primary_key;" json:"id"`
+	TenantID    string              `json:"tenant_id"`
+	OwnerID     string              `json:"owner_id,omitempty"`
+	Status      string              `json:"status"` // running, completed, interrupted
+	Total       int                 `json:"total"`
+	Succeeded   int                 `json:"succeeded"`
+	Failed      int                 `json:"failed"`
+	Filter      json.RawMessage     `gorm:"type:jsonb" json:"filter"`
+	Failures    []AnalysisBatchItem `gorm:"foreignKey:BatchID" json:"failures,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+}
+
+func (b *AnalysisBatch) BeforeCreate(tx *gorm.DB) (err error) {
+	b.ID = uuid.New()
+	return
+}
+
+type AnalysisBatchItem struct {
+	BatchID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	DocumentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"document_id"`
+	Status     string    `json:"status"` // pending, analyzed, failed
+	Error      string    `json:"error,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
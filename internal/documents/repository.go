@@ -1,48 +1,304 @@
 package documents
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/tenant"
 	"gorm.io/gorm"
 )
 
+// Repository methods are scoped to the tenant (and owner, if any) carried by
+// ctx; rows belonging to anyone else are reported as not found.
 type Repository interface {
-	Create(doc *Document) error
-	FindByID(id uuid.UUID) (*Document, error)
-	FindByFilename(filename string) (*Document, error)
+	Create(ctx context.Context, doc *Document) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Document, error)
+	FindByFilename(ctx context.Context, filename string) (*Document, error)
+	FindIDs(ctx context.Context, ids []uuid.UUID, filter *BatchFilter) ([]uuid.UUID, error)
+	FindIDsByStatus(ctx context.Context, status Status) ([]uuid.UUID, error)
+	List(ctx context.Context, filter ListFilter) ([]Document, error)
 	IsNotFoundError(err error) bool
-	Update(doc *Document) error
+	Update(ctx context.Context, doc *Document) error
+	CountByStatus(ctx context.Context) (map[Status]int64, error)
+
+	CreateBatch(ctx context.Context, batch *AnalysisBatch, documentIDs []uuid.UUID) error
+	FindBatchByID(ctx context.Context, id uuid.UUID) (*AnalysisBatch, error)
+	RecordBatchResult(ctx context.Context, batchID, documentID uuid.UUID, analysisErr error) error
+	FinishBatch(ctx context.Context, id uuid.UUID, status string) error
+
+	// ListDataKeys and UpdateDataKey support re-wrapping data keys after a
+	// master key rotation.
+	ListDataKeys(ctx context.Context, after uuid.UUID, limit int) ([]DataKeyRef, error)
+	UpdateDataKey(ctx context.Context, id uuid.UUID, previous, wrapped string) error
 }
 
+// DataKeyRef is a document's wrapped data key.
+type DataKeyRef struct {
+	ID      uuid.UUID
+	DataKey string
+}
+
+// repository encrypts the sensitive document columns with each document's
+// data key on the way in and decrypts them on the way out.
 type repository struct {
-	db *gorm.DB
+	db   *gorm.DB
+	keys *envelope.Keyring
 }
 
-func NewRepository(db *gorm.DB) Repository {
-	return &repository{db: db}
+func NewRepository(db *gorm.DB, keys *envelope.Keyring) Repository {
+	return &repository{db: db, keys: keys}
 }
 
-func (r *repository) Create(doc *Document) error {
-	return r.db.Create(doc).Error
+func (r *repository) Create(ctx context.Context, doc *Document) error {
+	sealed, err := r.seal(doc)
+	if err != nil {
+		return err
+	}
+
+	err = tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		if !scope.Allows(doc.TenantID, doc.OwnerID) {
+			return tenant.ErrOutOfScope
+		}
+		return tx.Create(sealed).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	doc.ID, doc.Version, doc.CreatedAt, doc.UpdatedAt = sealed.ID, sealed.Version, sealed.CreatedAt, sealed.UpdatedAt
+	return nil
 }
 
-func (r *repository) FindByID(id uuid.UUID) (*Document, error) {
+func (r *repository) FindByID(ctx context.Context, id uuid.UUID) (*Document, error) {
 	var doc Document
-	err := r.db.First(&doc, "id = ?", id).Error
-	return &doc, err
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.Filter(tx).First(&doc, "id = ?", id).Error
+	})
+	if err != nil {
+		return &doc, err
+	}
+	return &doc, r.open(&doc)
 }
 
-func (r *repository) FindByFilename(filename string) (*Document, error) {
+// FindByFilename skips documents rejected by the malware scan, so a clean
+// upload under the same name is stored and scanned rather than answered with
+// the quarantined record.
+func (r *repository) FindByFilename(ctx context.Context, filename string) (*Document, error) {
 	var doc Document
-	err := r.db.First(&doc, "filename = ?", filename).Error
-	return &doc, err
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.Filter(tx).First(&doc, "filename = ? AND status <> ?", filename, StatusRejected).Error
+	})
+	if err != nil {
+		return &doc, err
+	}
+	return &doc, r.open(&doc)
+}
+
+// FindIDs resolves batch targets. Explicit IDs are narrowed to documents that
+// exist; a filter matches documents that are not currently being processed.
+func (r *repository) FindIDs(ctx context.Context, ids []uuid.UUID, filter *BatchFilter) ([]uuid.UUID, error) {
+	var found []uuid.UUID
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		query := scope.Filter(tx.Model(&Document{}))
+		if len(ids) > 0 {
+			query = query.Where("id IN ?", ids)
+		}
+		if filter != nil {
+			query = query.Where("status NOT IN ?", []Status{StatusQueued, StatusProcessing})
+			if filter.Status != "" {
+				query = query.Where("status = ?", filter.Status)
+			}
+			if filter.DocType != "" {
+				query = query.Where("LOWER(doc_type) = LOWER(?)", filter.DocType)
+			}
+		}
+
+		return query.Order("created_at").Pluck("id", &found).Error
+	})
+	return found, err
 }
 
-func (r *repository) Update(doc *Document) error {
-	return r.db.Save(doc).Error
+// FindIDsByStatus lists the documents in a status, oldest first.
+func (r *repository) FindIDsByStatus(ctx context.Context, status Status) ([]uuid.UUID, error) {
+	var found []uuid.UUID
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.Filter(tx.Model(&Document{})).
+			Where("status = ?", status).
+			Order("created_at").
+			Pluck("id", &found).Error
+	})
+	return found, err
+}
+
+func (r *repository) List(ctx context.Context, filter ListFilter) ([]Document, error) {
+	list := []Document{}
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		query := scope.Filter(tx)
+		if filter.Status != "" {
+			query = query.Where("status = ?", filter.Status)
+		}
+		if filter.DocType != "" {
+			query = query.Where("LOWER(doc_type) = LOWER(?)", filter.DocType)
+		}
+		// the text is only served by FindByID, where reads are audited
+		return query.Omit("extracted_text").Order("created_at DESC, id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&list).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		if err := r.open(&list[i]); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// Update writes the document back using optimistic locking: the write only
+// succeeds if nobody else has updated the row since it was read, otherwise
+// ErrStaleDocument is returned.
+func (r *repository) Update(ctx context.Context, doc *Document) error {
+	readVersion := doc.Version
+	doc.Version++
+
+	sealed, err := r.seal(doc)
+	if err != nil {
+		doc.Version = readVersion
+		return err
+	}
+
+	err = tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		result := scope.Filter(tx.Model(sealed)).
+			Where("version = ?", readVersion).
+			Select("*").
+			Omit("id", "tenant_id", "owner_id", "data_key", "created_at").
+			Updates(sealed)
+
+		if result.Error == nil && result.RowsAffected == 0 {
+			return ErrStaleDocument
+		}
+		return result.Error
+	})
+
+	if err != nil {
+		doc.Version = readVersion
+		return err
+	}
+	doc.UpdatedAt = sealed.UpdatedAt
+	return nil
+}
+
+func (r *repository) ListDataKeys(ctx context.Context, after uuid.UUID, limit int) ([]DataKeyRef, error) {
+	var refs []DataKeyRef
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.Filter(tx.Model(&Document{})).
+			Select("id", "data_key").
+			Where("id > ? AND data_key <> ''", after).
+			Order("id").
+			Limit(limit).
+			Scan(&refs).Error
+	})
+	return refs, err
+}
+
+// UpdateDataKey swaps a wrapped data key, provided it hasn't changed since it
+// was listed. Only the key is touched, so the version is left alone.
+func (r *repository) UpdateDataKey(ctx context.Context, id uuid.UUID, previous, wrapped string) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		result := scope.Filter(tx.Model(&Document{})).
+			Where("id = ? AND data_key = ?", id, previous).
+			UpdateColumn("data_key", wrapped)
+		if result.Error == nil && result.RowsAffected == 0 {
+			return ErrStaleDocument
+		}
+		return result.Error
+	})
+}
+
+func (r *repository) CountByStatus(ctx context.Context) (map[Status]int64, error) {
+	var rows []struct {
+		Status Status
+		Count  int64
+	}
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.Filter(tx.Model(&Document{})).
+			Select("status, COUNT(*) AS count").
+			Group("status").
+			Scan(&rows).Error
+	})
+
+	counts := make(map[Status]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, err
 }
 
 func (r *repository) IsNotFoundError(err error) bool {
 	return errors.Is(err, gorm.ErrRecordNotFound)
 }
+
+func (r *repository) CreateBatch(ctx context.Context, batch *AnalysisBatch, documentIDs []uuid.UUID) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		if !scope.Allows(batch.TenantID, batch.OwnerID) {
+			return tenant.ErrOutOfScope
+		}
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+
+		items := make([]AnalysisBatchItem, 0, len(documentIDs))
+		for _, docID := range documentIDs {
+			items = append(items, AnalysisBatchItem{BatchID: batch.ID, DocumentID: docID, Status: "pending"})
+		}
+		return tx.CreateInBatches(items, 500).Error
+	})
+}
+
+// FindBatchByID loads a batch together with its failed items.
+func (r *repository) FindBatchByID(ctx context.Context, id uuid.UUID) (*AnalysisBatch, error) {
+	var batch AnalysisBatch
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.Filter(tx).
+			Preload("Failures", "status = ?", "failed").
+			First(&batch, "id = ?", id).Error
+	})
+	return &batch, err
+}
+
+func (r *repository) RecordBatchResult(ctx context.Context, batchID, documentID uuid.UUID, analysisErr error) error {
+	status, counter, message := "analyzed", "succeeded", ""
+	if analysisErr != nil {
+		status, counter, message = "failed", "failed", analysisErr.Error()
+	}
+
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		result := scope.Filter(tx.Model(&AnalysisBatch{})).
+			Where("id = ?", batchID).
+			Update(counter, gorm.Expr(counter+" + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Model(&AnalysisBatchItem{}).
+			Where("batch_id = ? AND document_id = ?", batchID, documentID).
+			Updates(map[string]interface{}{"status": status, "error": message, "updated_at": time.Now()}).Error
+	})
+}
+
+// FinishBatch ends a batch as completed, or as interrupted when shutdown
+// stopped it before every document was analyzed.
+func (r *repository) FinishBatch(ctx context.Context, id uuid.UUID, status string) error {
+	now := time.Now()
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.Filter(tx.Model(&AnalysisBatch{})).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"status": status, "completed_at": &now}).Error
+	})
+}
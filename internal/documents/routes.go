@@ -2,10 +2,19 @@ package documents
 
 import (
 	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/auth"
 )
 
 func RegisterRoutes(r *mux.Router, h *Handler) {
-	r.HandleFunc("/documents/upload", h.UploadDocument).Methods("POST")
-	r.HandleFunc("/documents/{id}/analyze", h.AnalyzeDocument).Methods("POST")
-	r.HandleFunc("/documents/{id}", h.GetDocument).Methods("GET")
+	idempotent := h.config.Idempotency
+	r.HandleFunc("/documents", auth.RequireScope(auth.ScopeRead, h.ListDocuments)).Methods("GET")
+	r.HandleFunc("/documents/upload", auth.RequireScope(auth.ScopeWrite, idempotent.Handle(h.uploadLimit, h.UploadDocument))).Methods("POST")
+	r.HandleFunc("/documents/analyze", auth.RequireScope(auth.ScopeAnalyze, idempotent.Handle(jsonLimit, h.AnalyzeBatch))).Methods("POST")
+	r.HandleFunc("/documents/batches/{id}", auth.RequireScope(auth.ScopeRead, h.GetBatch)).Methods("GET")
+	r.HandleFunc("/documents/{id}/analyze", auth.RequireScope(auth.ScopeAnalyze, idempotent.Handle(jsonLimit, h.AnalyzeDocument))).Methods("POST")
+	r.HandleFunc("/documents/{id}/events", h.service.audit.Track(audit.ActionView, auth.RequireScope(auth.ScopeRead, h.StreamDocumentEvents))).Methods("GET")
+	r.HandleFunc("/documents/{id}/download", h.service.audit.Track(audit.ActionDownload, auth.RequireScope(auth.ScopeRead, h.DownloadDocument))).Methods("GET")
+	r.HandleFunc("/documents/{id}", h.service.audit.Track(audit.ActionView, auth.RequireScope(auth.ScopeRead, h.GetDocument))).Methods("GET")
+	r.HandleFunc("/events", auth.RequireScope(auth.ScopeRead, h.StreamEvents)).Methods("GET")
 }
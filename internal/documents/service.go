@@ -4,44 +4,139 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/audit"
 	"github.com/zjoart/docai/internal/documents/analyzer"
 	"github.com/zjoart/docai/internal/documents/extractor"
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/scanner"
 	"github.com/zjoart/docai/internal/storage"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/internal/tracing"
+	"github.com/zjoart/docai/internal/upload"
 	"github.com/zjoart/docai/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// QuarantinePrefix is where infected uploads are stored, away from the
+// per-tenant prefixes. The bucket must not allow anonymous reads: files are
+// only served through the download endpoint, which refuses rejected
+// documents.
+const QuarantinePrefix = "quarantine/"
+
 type Service struct {
 	repo     Repository
 	storage  *storage.Client
 	analyzer *analyzer.Analyzer
+	events   *events.Bus
+	quotas   *quota.Service
+	redactor *pii.Redactor
+	keys     *envelope.Keyring
+	scanner  scanner.Scanner
+	audit    *audit.Service
+	metrics  Metrics
+	uploads  *upload.Policies
+	jobs     *jobs
 }
 
-func NewService(repo Repository, storage *storage.Client, analyzer *analyzer.Analyzer) *Service {
+// NewService wires the document pipeline. scanner may be nil to skip malware
+// scanning, metrics may be nil to discard measurements and uploads may be nil
+// to apply upload.Default to every tenant.
+func NewService(repo Repository, storage *storage.Client, analyzer *analyzer.Analyzer, bus *events.Bus, quotas *quota.Service, redactor *pii.Redactor, keys *envelope.Keyring, scanner scanner.Scanner, audit *audit.Service, metrics Metrics, uploads *upload.Policies) *Service {
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+
 	return &Service{
 		repo:     repo,
 		storage:  storage,
 		analyzer: analyzer,
+		events:   bus,
+		quotas:   quotas,
+		redactor: redactor,
+		keys:     keys,
+		scanner:  scanner,
+		audit:    audit,
+		metrics:  metrics,
+		uploads:  uploads,
+		jobs:     newJobs(),
 	}
 }
 
+// AnalysisResult is the payload published with document.analyzed events.
+type AnalysisResult struct {
+	Summary  string          `json:"summary"`
+	DocType  string          `json:"doc_type"`
+	Metadata json.RawMessage `json:"metadata"`
+}
+
+// UploadDocument stores and extracts an upload. Every attempt is audited.
 func (s *Service) UploadDocument(ctx context.Context, filename string, reader io.Reader, size int64, contentType string) (*Document, error) {
+	ctx, span := tracing.Start(ctx, "documents.UploadDocument",
+		attribute.String("document.filename", filename),
+		attribute.Int64("document.size_bytes", size),
+	)
+	doc, err := s.upload(ctx, filename, reader, size, contentType)
+	err = s.apiError(err)
+	if doc != nil {
+		span.SetAttributes(attribute.String("document.id", doc.ID.String()))
+	}
+	tracing.End(span, err)
+
+	event := audit.Event{Action: audit.ActionUpload, Outcome: s.auditOutcome(err), Detail: filename}
+	if doc != nil {
+		event.DocumentID = &doc.ID
+		event.TenantID = doc.TenantID
+	}
+	if err != nil {
+		event.Detail = fmt.Sprintf("%s: %v", filename, err)
+	}
+	s.audit.Record(ctx, event)
+
+	return doc, err
+}
+
+// UploadPolicy returns the upload policy of the caller's tenant.
+func (s *Service) UploadPolicy(ctx context.Context) upload.Policy {
+	scope, _ := tenant.FromContext(ctx)
+	return s.uploads.For(scope.TenantID)
+}
+
+func (s *Service) upload(ctx context.Context, filename string, reader io.Reader, size int64, contentType string) (*Document, error) {
+
+	scope, ok := tenant.FromContext(ctx)
+	if !ok || scope.IsSystem() {
+		return nil, tenant.ErrNoScope
+	}
 
+	policy := s.uploads.For(scope.TenantID)
+	if err := policy.Check(filename, size); err != nil {
+		return nil, err
+	}
+
+	// read one byte past the limit, so a size that was understated is caught
 	buf := new(bytes.Buffer)
-	if _, err := io.Copy(buf, reader); err != nil {
-		logger.Error("Failed to read upload content", logger.WithError(err))
+	if _, err := io.Copy(buf, io.LimitReader(reader, policy.Limit()+1)); err != nil {
+		logger.FromContext(ctx).Error("Failed to read upload content", logger.WithError(err))
+		return nil, err
+	}
+	if err := policy.Check(filename, int64(buf.Len())); err != nil {
 		return nil, err
 	}
 
-	existingDoc, err := s.repo.FindByFilename(filename)
+	existingDoc, err := s.repo.FindByFilename(ctx, filename)
 	if err == nil {
-		logger.Info("Document already exists, returning existing record", logger.Fields{"filename": filename, "id": existingDoc.ID})
+		logger.FromContext(ctx).Info("Document already exists, returning existing record", logger.Fields{"filename": filename, "id": existingDoc.ID})
 		return existingDoc, nil
 	}
 
@@ -51,105 +146,410 @@ func (s *Service) UploadDocument(ctx context.Context, filename string, reader io
 
 	fileBytes := buf.Bytes()
 
-	ext := filepath.Ext(filename)
-	objectName := fmt.Sprintf("%d_%s", time.Now().Unix(), filename)
+	if err := s.quotas.CheckStorage(ctx, scope.TenantID, int64(len(fileBytes))); err != nil {
+		return nil, err
+	}
 
-	var extractedText string
-	switch ext {
-	case ".pdf":
-		extractedText, err = extractor.ExtractTextFromPDF(bytes.NewReader(fileBytes), int64(len(fileBytes)))
+	// build the record up front so extraction progress can be attributed to it
+	doc := &Document{
+		ID:          uuid.New(),
+		TenantID:    scope.TenantID,
+		OwnerID:     scope.OwnerID,
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   int64(len(fileBytes)),
+		Status:      StatusUploaded,
+	}
+	ctx = logger.WithDocumentID(ctx, doc.ID.String())
+	reportProgress := func(current, total int) {
+		s.publish(doc, events.Event{
+			Type:     events.Progress,
+			Progress: &events.ProgressInfo{Stage: events.StageExtraction, Current: current, Total: total},
+		})
+	}
+
+	// scan before the file is parsed or stored anywhere it could be served from
+	if s.scanner != nil {
+		result, err := s.scanner.Scan(ctx, bytes.NewReader(fileBytes))
 		if err != nil {
-			logger.Warn("Failed to extract text from PDF", logger.Merge(logger.Fields{"filename": filename}, logger.WithError(err)))
-			return nil, fmt.Errorf("failed to extract text from PDF/Image")
+			logger.FromContext(ctx).Error("Malware scan failed", logger.Merge(logger.Fields{"filename": filename}, logger.WithError(err)))
+			return nil, err
 		}
+		if result.Infected {
+			return s.quarantine(ctx, doc, fileBytes, result.Signature)
+		}
+	}
 
-	case ".docx":
-		extractedText, err = extractor.ExtractTextFromDOCX(bytes.NewReader(fileBytes), int64(len(fileBytes)))
+	objectName := fmt.Sprintf("%s/%d_%s", scope.TenantID, time.Now().Unix(), filename)
+	format := extractor.FormatOf(filename)
+	s.metrics.ObserveUpload(format, int64(len(fileBytes)))
+
+	if format == extractor.FormatPDF && policy.MaxPages > 0 {
+		pages, err := extractor.CountPDFPages(bytes.NewReader(fileBytes), int64(len(fileBytes)))
 		if err != nil {
-			logger.Warn("Failed to extract text from DOCX", logger.Merge(logger.Fields{"filename": filename}, logger.WithError(err)))
-			return nil, fmt.Errorf("failed to extract text from DOCX: %w", err)
+			logger.FromContext(ctx).Warn("Failed to count PDF pages", logger.WithError(err))
+			return nil, apierror.Wrap(apierror.CodeExtractionFailed, "Failed to extract text from PDF/Image", err)
+		}
+		if pages > policy.MaxPages {
+			return nil, apierror.Wrap(apierror.CodeTooLarge, fmt.Sprintf("Document has too many pages (%d, max %d)", pages, policy.MaxPages), upload.ErrTooManyPages)
 		}
+	}
 
-	case ".txt":
-		extractedText = string(fileBytes)
+	extractedText, err := s.extract(ctx, format, fileBytes, reportProgress)
+	if err != nil {
+		return nil, err
 	}
 
 	if strings.TrimSpace(extractedText) == "" {
-		return nil, fmt.Errorf("upload rejected: no text could be extracted from document")
+		return nil, apierror.New(apierror.CodeExtractionFailed, "Upload rejected: no text could be extracted from document")
 	}
 
-	fileUrl, err := s.storage.UploadFile(ctx, objectName, bytes.NewReader(fileBytes), int64(len(fileBytes)), contentType)
+	dataKey, wrappedKey, err := s.keys.NewDataKey()
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload: %w", err)
+		return nil, fmt.Errorf("failed to create data key: %w", err)
 	}
+	doc.DataKey = wrappedKey
 
-	doc := &Document{
-		Filename:      filename,
-		ContentType:   contentType,
-		StoragePath:   objectName,
-		FileUrl:       fileUrl,
-		ExtractedText: extractedText,
-		Status:        "uploaded",
+	if err := s.storage.UploadFile(ctx, objectName, bytes.NewReader(fileBytes), int64(len(fileBytes)), contentType, dataKey); err != nil {
+		return nil, fmt.Errorf("failed to upload: %w", err)
 	}
 
-	if err := s.repo.Create(doc); err != nil {
-		logger.Error("Failed to create document record", logger.WithError(err))
+	doc.StoragePath = objectName
+	doc.FileUrl = downloadPath(doc.ID)
+	doc.ExtractedText = extractedText
+
+	if err := s.repo.Create(ctx, doc); err != nil {
+		logger.FromContext(ctx).Error("Failed to create document record", logger.WithError(err))
 
 		//  delete file from storage
 		if delErr := s.storage.DeleteFile(ctx, objectName); delErr != nil {
-			logger.Error("Failed to delete orphaned file", logger.Merge(logger.Fields{"object": objectName}, logger.WithError(delErr)))
+			logger.FromContext(ctx).Error("Failed to delete orphaned file", logger.Merge(logger.Fields{"object": objectName}, logger.WithError(delErr)))
 		}
 		return nil, err
 	}
 
-	logger.Info("Document uploaded successfully", logger.Fields{"id": doc.ID, "filename": filename})
+	logger.FromContext(ctx).Info("Document uploaded successfully", logger.Fields{"filename": filename})
+
+	s.publish(doc, events.Event{
+		Type: events.DocumentUploaded,
+		Data: map[string]string{"filename": doc.Filename, "content_type": doc.ContentType},
+	})
 
 	return doc, nil
 }
 
+// extract pulls the text out of an upload, timing it per format.
+func (s *Service) extract(ctx context.Context, format string, fileBytes []byte, reportProgress func(current, total int)) (text string, err error) {
+	ctx, span := tracing.Start(ctx, "extractor."+format,
+		attribute.String("document.format", format),
+		attribute.Int("document.size_bytes", len(fileBytes)),
+	)
+	started := time.Now()
+	defer func() {
+		s.metrics.ObserveExtraction(format, time.Since(started), err)
+		span.SetAttributes(attribute.Int("document.text_length", len(text)))
+		tracing.End(span, err)
+	}()
+
+	result, err := extractor.Extract(format, bytes.NewReader(fileBytes), int64(len(fileBytes)), reportProgress)
+	if err != nil {
+		logger.FromContext(ctx).Warn("Failed to extract text", logger.Merge(logger.Fields{"format": format}, logger.WithError(err)))
+		switch format {
+		case extractor.FormatPDF:
+			return "", apierror.Wrap(apierror.CodeExtractionFailed, "Failed to extract text from PDF/Image", err)
+		case extractor.FormatDOCX:
+			return "", apierror.Wrap(apierror.CodeExtractionFailed, "Failed to extract text from DOCX", err)
+		}
+		return "", apierror.Wrap(apierror.CodeExtractionFailed, "Failed to extract text", err)
+	}
+	if len(result.Warnings) > 0 {
+		logger.FromContext(ctx).Warn("Extraction warnings", logger.Fields{"format": format, "warnings": result.Warnings})
+	}
+	return result.Text, nil
+}
+
+// quarantine stores an infected upload under the quarantine prefix, where it
+// is never linked to, and records the document as rejected.
+func (s *Service) quarantine(ctx context.Context, doc *Document, fileBytes []byte, signature string) (*Document, error) {
+	logger.FromContext(ctx).Warn("Malware detected in upload", logger.Fields{"filename": doc.Filename, "tenant_id": doc.TenantID, "signature": signature})
+
+	dataKey, wrappedKey, err := s.keys.NewDataKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create data key: %w", err)
+	}
+
+	objectName := fmt.Sprintf("%s%s/%d_%s", QuarantinePrefix, doc.TenantID, time.Now().Unix(), doc.Filename)
+	if err := s.storage.UploadFile(ctx, objectName, bytes.NewReader(fileBytes), int64(len(fileBytes)), doc.ContentType, dataKey); err != nil {
+		return nil, fmt.Errorf("failed to quarantine upload: %w", err)
+	}
+
+	doc.DataKey = wrappedKey
+	doc.StoragePath = objectName
+	doc.Status = StatusRejected
+	doc.FailureReason = "malware detected: " + signature
+
+	if err := s.repo.Create(ctx, doc); err != nil {
+		logger.FromContext(ctx).Error("Failed to record rejected document", logger.WithError(err))
+		return nil, err
+	}
+
+	s.publish(doc, events.Event{
+		Type:  events.DocumentRejected,
+		Error: doc.FailureReason,
+	})
+
+	return doc, ErrMalwareDetected
+}
+
+// AnalyzeDocument runs LLM analysis on a document. The document is queued if
+// needed and then claimed by moving it to processing; because status updates
+// use optimistic locking, only one concurrent caller can claim it and the
+// others get ErrAlreadyProcessing. Failures leave the document in the failed
+// status with the reason recorded, except when shutdown interrupts the
+// analysis, which hands the document back to the queue. Each analysis counts
+// against the tenant's daily quota and monthly token budget; a
+// *quota.ExceededError leaves the document untouched.
+//
+// Every attempt is audited, including those of batches and background runs.
 func (s *Service) AnalyzeDocument(ctx context.Context, id uuid.UUID) (*Document, error) {
-	doc, err := s.repo.FindByID(id)
+	ctx = logger.WithDocumentID(ctx, id.String())
+	ctx, span := tracing.Start(ctx, "documents.AnalyzeDocument", attribute.String("document.id", id.String()))
+	doc, err := s.analyze(ctx, id)
+	err = s.apiError(err)
+	tracing.End(span, err)
+
+	event := audit.Event{Action: audit.ActionAnalyze, DocumentID: &id, Outcome: s.auditOutcome(err)}
+	if doc != nil {
+		event.TenantID = doc.TenantID
+	}
+	if err != nil {
+		event.Detail = err.Error()
+	}
+	s.audit.Record(ctx, event)
+
+	return doc, err
+}
+
+func (s *Service) analyze(ctx context.Context, id uuid.UUID) (*Document, error) {
+	doc, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		logger.Error("Document not found for analysis", logger.Merge(logger.Fields{"id": id}, logger.WithError(err)))
+		logger.FromContext(ctx).Error("Document not found for analysis", logger.WithError(err))
+		return nil, err
+	}
+
+	if err := s.quotas.ReserveAnalysis(ctx, doc.TenantID); err != nil {
+		logger.FromContext(ctx).Warn("Analysis refused by quota", logger.Merge(logger.Fields{"tenant_id": doc.TenantID}, logger.WithError(err)))
+		return nil, err
+	}
+
+	if err := s.claim(ctx, doc); err != nil {
+		s.quotas.ReleaseAnalysis(ctx, doc.TenantID)
 		return nil, err
 	}
 
 	if strings.TrimSpace(doc.ExtractedText) == "" {
-		logger.Warn("Skipping analysis: No text extracted", logger.Fields{"id": id})
+		logger.FromContext(ctx).Warn("Skipping analysis: No text extracted")
+		s.quotas.ReleaseAnalysis(ctx, doc.TenantID)
+
+		err := apierror.New(apierror.CodeExtractionFailed, "Analysis skipped: no text extracted from document (likely scanned PDF or image)")
+		s.fail(ctx, doc, err)
+		return doc, err
+	}
+
+	s.publish(doc, events.Event{
+		Type:     events.Progress,
+		Progress: &events.ProgressInfo{Stage: events.StageAnalysis, Current: 0, Total: 1},
+	})
 
-		return doc, fmt.Errorf("analysis skipped: no text extracted from document (likely scanned PDF or image)")
+	// PII is swapped for placeholders before the text leaves the service and
+	// put back into the result afterwards, as the tenant's policy allows.
+	redaction := s.redactor.Redact(doc.TenantID, doc.ExtractedText)
+	if counts := redaction.Counts(); len(counts) > 0 {
+		logger.FromContext(ctx).Info("Redacted PII before analysis", logger.Fields{"mode": redaction.Policy.Mode, "counts": counts})
 	}
 
-	result, err := s.analyzer.AnalyzeText(ctx, doc.ExtractedText)
+	result, err := s.analyzer.AnalyzeText(ctx, redaction.Text)
+	if result != nil {
+		s.quotas.RecordTokens(ctx, doc.TenantID, result.TokensUsed)
+	}
 	if err != nil {
-		logger.Error("LLM analysis failed", logger.Merge(logger.Fields{"id": id}, logger.WithError(err)))
-		return nil, err
+		if s.interrupted(ctx) {
+			s.requeue(ctx, doc)
+			return nil, err
+		}
+		logger.FromContext(ctx).Error("LLM analysis failed", logger.WithError(err))
+		s.fail(ctx, doc, err)
+		return nil, apierror.Wrap(apierror.CodeUpstreamLLM, "Analysis failed: the language model request did not succeed", err)
 	}
 
-	metaBytes, _ := json.Marshal(result.Metadata)
+	metaBytes, _ := json.Marshal(redaction.RestoreValue(result.Metadata))
 
-	doc.Summary = result.Summary
+	doc.Summary = redaction.Restore(result.Summary)
 	doc.DocType = result.Type
 	doc.Metadata = metaBytes
-	doc.Status = "analyzed"
 
-	if err := s.repo.Update(doc); err != nil {
+	if err := s.transition(ctx, doc, StatusAnalyzed, ""); err != nil {
+		if s.interrupted(ctx) {
+			s.requeue(ctx, doc)
+		}
 		return nil, err
 	}
 
+	s.publish(doc, events.Event{
+		Type: events.DocumentAnalyzed,
+		Data: AnalysisResult{Summary: doc.Summary, DocType: doc.DocType, Metadata: doc.Metadata},
+	})
+
 	return doc, nil
 }
 
 func (s *Service) GetDocument(ctx context.Context, id uuid.UUID) (*Document, error) {
+	doc, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, s.apiError(err)
+	}
+	return doc, nil
+}
+
+// ListDocuments returns the documents visible in ctx, newest first, without
+// their extracted text. Fetching a single document, which is audited, is the
+// only way to read it.
+func (s *Service) ListDocuments(ctx context.Context, filter ListFilter) ([]Document, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CountByStatus counts the documents visible in ctx by status.
+func (s *Service) CountByStatus(ctx context.Context) (map[Status]int64, error) {
+	return s.repo.CountByStatus(ctx)
+}
 
-	return s.repo.FindByID(id)
+func (s *Service) IsNotFoundError(err error) bool {
+	return s.repo.IsNotFoundError(err)
 }
 
-func (s *Service) UpdateStatus(ctx context.Context, id uuid.UUID, status string) error {
-	doc, err := s.repo.FindByID(id)
+// OpenFile returns the document and its decrypted file content. Rejected
+// uploads stay in quarantine and cannot be downloaded.
+func (s *Service) OpenFile(ctx context.Context, id uuid.UUID) (*Document, io.ReadCloser, error) {
+	ctx = logger.WithDocumentID(ctx, id.String())
+	doc, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, nil, s.apiError(err)
+	}
+	if doc.Status == StatusRejected {
+		return doc, nil, apierror.Wrap(apierror.CodeForbidden, "Document was rejected by the malware scan", ErrMalwareDetected)
+	}
+
+	dataKey, err := s.keys.Unwrap(doc.DataKey)
 	if err != nil {
+		return nil, nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+
+	content, err := s.storage.GetFileContent(ctx, doc.StoragePath, dataKey)
+	if err != nil {
+		logger.FromContext(ctx).Error("Failed to read stored file", logger.WithError(err))
+		return nil, nil, err
+	}
+	return doc, content, nil
+}
+
+// auditOutcome treats requests for documents outside the caller's scope, and
+// requests refused by a quota, as denials.
+func (s *Service) auditOutcome(err error) audit.Outcome {
+	_, exceeded := quota.AsExceeded(err)
+	switch {
+	case err == nil:
+		return audit.OutcomeSuccess
+	case exceeded, s.IsNotFoundError(err), errors.Is(err, tenant.ErrOutOfScope), errors.Is(err, tenant.ErrNoScope):
+		return audit.OutcomeDenied
+	default:
+		return audit.OutcomeFailure
+	}
+}
+
+// UpdateStatus moves a document to a new status, enforcing the allowed
+// transitions.
+func (s *Service) UpdateStatus(ctx context.Context, id uuid.UUID, status Status) (*Document, error) {
+	doc, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, s.apiError(err)
+	}
+
+	if err := s.transition(ctx, doc, status, ""); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// claim queues the document if necessary and moves it to processing.
+func (s *Service) claim(ctx context.Context, doc *Document) error {
+	if doc.Status == StatusProcessing {
+		return ErrAlreadyProcessing
+	}
+
+	if doc.Status != StatusQueued {
+		if err := s.transition(ctx, doc, StatusQueued, ""); err != nil {
+			return claimError(err)
+		}
+	}
+
+	if err := s.transition(ctx, doc, StatusProcessing, ""); err != nil {
+		return claimError(err)
+	}
+	return nil
+}
+
+func claimError(err error) error {
+	if errors.Is(err, ErrStaleDocument) {
+		return ErrAlreadyProcessing
+	}
+	return err
+}
+
+// fail records why analysis failed and moves the document to failed.
+func (s *Service) fail(ctx context.Context, doc *Document, cause error) {
+	if err := s.transition(ctx, doc, StatusFailed, cause.Error()); err != nil {
+		logger.FromContext(ctx).Error("Failed to mark document as failed", logger.WithError(err))
+	}
+
+	s.publish(doc, events.Event{
+		Type:  events.DocumentFailed,
+		Error: cause.Error(),
+	})
+}
+
+// transition validates and persists a status change, then publishes it.
+func (s *Service) transition(ctx context.Context, doc *Document, next Status, reason string) error {
+	if !doc.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, doc.Status, next)
+	}
+
+	previous, previousReason, previousAttempts := doc.Status, doc.FailureReason, doc.Attempts
+
+	doc.Status = next
+	doc.FailureReason = reason
+	if next == StatusProcessing {
+		doc.Attempts++
+	}
+
+	if err := s.repo.Update(ctx, doc); err != nil {
+		doc.Status, doc.FailureReason, doc.Attempts = previous, previousReason, previousAttempts
 		return err
 	}
-	doc.Status = status
-	return s.repo.Update(doc)
+
+	s.publish(doc, events.Event{
+		Type:           events.StatusChanged,
+		PreviousStatus: string(previous),
+	})
+	return nil
+}
+
+// publish fills in the document fields of an event and puts it on the bus.
+func (s *Service) publish(doc *Document, e events.Event) {
+	e.DocumentID = doc.ID
+	e.TenantID = doc.TenantID
+	e.OwnerID = doc.OwnerID
+	if e.Status == "" {
+		e.Status = string(doc.Status)
+	}
+	s.events.Publish(e)
 }
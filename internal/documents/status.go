@@ -0,0 +1,53 @@
+package documents
+
+import "github.com/zjoart/docai/internal/apierror"
+
+type Status string
+
+const (
+	StatusUploaded   Status = "uploaded"
+	StatusQueued     Status = "queued"
+	StatusProcessing Status = "processing"
+	StatusAnalyzed   Status = "analyzed"
+	StatusFailed     Status = "failed"
+	// StatusRejected is final: the upload failed the malware scan and its
+	// file was quarantined.
+	StatusRejected Status = "rejected"
+)
+
+var (
+	ErrInvalidTransition = apierror.Conflict("Invalid status transition")
+	ErrAlreadyProcessing = apierror.Conflict("Document is already being processed")
+	// ErrStaleDocument is returned when a document was modified by someone
+	// else between being read and written back.
+	ErrStaleDocument = apierror.Conflict("Document was modified concurrently")
+	// ErrMalwareDetected is returned, together with the rejected document,
+	// when an upload fails the malware scan.
+	ErrMalwareDetected = apierror.New(apierror.CodeMalwareDetected, "File rejected: malware detected")
+)
+
+// transitions lists the statuses each status may move to. A document is
+// queued before it is analyzed; processing ends in analyzed or failed, or
+// goes back to queued when work is interrupted and handed back.
+var transitions = map[Status][]Status{
+	StatusUploaded:   {StatusQueued},
+	StatusQueued:     {StatusProcessing},
+	StatusProcessing: {StatusAnalyzed, StatusFailed, StatusQueued},
+	StatusAnalyzed:   {StatusQueued},
+	StatusFailed:     {StatusQueued},
+	StatusRejected:   {},
+}
+
+func (s Status) CanTransitionTo(next Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s Status) IsValid() bool {
+	_, ok := transitions[s]
+	return ok
+}
@@ -0,0 +1,149 @@
+package documents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/id"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	sseBufferSize        = 64
+	// sseWriteTimeout bounds each write to a stream, replacing the server's
+	// write timeout which would otherwise cut streams off.
+	sseWriteTimeout = 10 * time.Second
+)
+
+// StreamDocumentEvents pushes status transitions, progress and results for a
+// single document as server-sent events. The current status is sent first so
+// clients don't miss a transition that happened before they connected.
+func (h *Handler) StreamDocumentEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	docID, err := id.IsValidUUID(vars["id"])
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid file ID format"))
+		return
+	}
+
+	ch, unsubscribe := h.service.events.Subscribe(events.ForDocument(docID), sseBufferSize)
+	defer unsubscribe()
+
+	doc, err := h.service.GetDocument(r.Context(), docID)
+	if err != nil {
+		apierror.Write(w, r, err)
+		return
+	}
+
+	h.streamEvents(w, r, ch, &events.Event{
+		Type:       events.StatusChanged,
+		DocumentID: doc.ID,
+		TenantID:   doc.TenantID,
+		OwnerID:    doc.OwnerID,
+		Status:     string(doc.Status),
+		Timestamp:  doc.UpdatedAt,
+	})
+}
+
+// StreamEvents pushes events for every document visible to the caller as
+// server-sent events.
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	scope, ok := tenant.FromContext(r.Context())
+	if !ok {
+		apierror.Write(w, r, apierror.Forbidden("No tenant scope"))
+		return
+	}
+
+	ch, unsubscribe := h.service.events.Subscribe(func(e events.Event) bool {
+		return scope.Allows(e.TenantID, e.OwnerID)
+	}, sseBufferSize)
+	defer unsubscribe()
+
+	h.streamEvents(w, r, ch, nil)
+}
+
+func (h *Handler) streamEvents(w http.ResponseWriter, r *http.Request, ch <-chan events.Event, initial *events.Event) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		apierror.Write(w, r, apierror.Internal("Streaming not supported"))
+		return
+	}
+
+	// streams outlive the server's write timeout, so each write gets its own
+	// deadline instead
+	rc := http.NewResponseController(w)
+	extendDeadline := func() {
+		if err := rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			logger.FromContext(r.Context()).Debug("Failed to set stream write deadline", logger.WithError(err))
+		}
+	}
+	extendDeadline()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if initial != nil {
+		if err := writeSSE(w, *initial); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-h.closing:
+			return
+
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			extendDeadline()
+			if err := writeSSE(w, e); err != nil {
+				logger.FromContext(r.Context()).Debug("Event stream closed", logger.WithError(err))
+				return
+			}
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			extendDeadline()
+			if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSE(w http.ResponseWriter, e events.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if e.ID != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", e.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.Type, data)
+	return err
+}
@@ -0,0 +1,174 @@
+package envelope
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvKeyID is the ID of the master key given directly in configuration.
+const EnvKeyID = "env"
+
+const keySize = 32
+
+var (
+	ErrNoKeyring  = errors.New("data is encrypted but no master key is configured")
+	ErrUnknownKey = errors.New("data key was wrapped with an unknown master key")
+)
+
+// Keyring holds the master keys that wrap per-document data keys. New data
+// keys are wrapped with the active key; older keys stay available for
+// unwrapping until everything has been re-wrapped.
+//
+// A nil *Keyring means encryption is disabled: no data keys are issued and
+// only plaintext can be read.
+type Keyring struct {
+	keys   map[string][]byte
+	active string
+}
+
+func NewKeyring(active string, keys map[string][]byte) (*Keyring, error) {
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("active master key %q is not in the keyring", active)
+	}
+	for id, key := range keys {
+		if len(key) != keySize {
+			return nil, fmt.Errorf("master key %q must be %d bytes, got %d", id, keySize, len(key))
+		}
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid master key id %q", id)
+		}
+	}
+	return &Keyring{keys: keys, active: active}, nil
+}
+
+type keyFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+// LoadKeyring builds a keyring from a base64 master key and/or a keyfile of
+// the form {"active": "2024-06", "keys": {"2024-06": "<base64>", ...}}. When
+// both are given the config key is added as EnvKeyID and the keyfile's active
+// key wins. It returns nil if neither is set.
+func LoadKeyring(masterKey, path string) (*Keyring, error) {
+	if masterKey == "" && path == "" {
+		return nil, nil
+	}
+
+	keys := map[string][]byte{}
+	active := ""
+
+	if masterKey != "" {
+		key, err := base64.StdEncoding.DecodeString(masterKey)
+		if err != nil {
+			return nil, fmt.Errorf("master key is not valid base64: %w", err)
+		}
+		keys[EnvKeyID] = key
+		active = EnvKeyID
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keyfile: %w", err)
+		}
+		var file keyFile
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("failed to parse keyfile: %w", err)
+		}
+		for id, encoded := range file.Keys {
+			key, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("keyfile key %q is not valid base64: %w", id, err)
+			}
+			keys[id] = key
+		}
+		active = file.Active
+	}
+
+	return NewKeyring(active, keys)
+}
+
+// ActiveKeyID returns the ID of the key new data keys are wrapped with.
+func (k *Keyring) ActiveKeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.active
+}
+
+// NewDataKey returns a fresh data key and its wrapped form for storage. With
+// encryption disabled both are empty.
+func (k *Keyring) NewDataKey() ([]byte, string, error) {
+	if k == nil {
+		return nil, "", nil
+	}
+
+	dek := make([]byte, keySize)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, "", err
+	}
+
+	wrapped, err := k.wrap(k.active, dek)
+	if err != nil {
+		return nil, "", err
+	}
+	return dek, wrapped, nil
+}
+
+// Unwrap recovers a data key. An empty wrapped key belongs to data stored
+// before encryption was enabled and unwraps to nil.
+func (k *Keyring) Unwrap(wrapped string) ([]byte, error) {
+	if wrapped == "" {
+		return nil, nil
+	}
+	if k == nil {
+		return nil, ErrNoKeyring
+	}
+
+	id, sealed, ok := strings.Cut(wrapped, ":")
+	if !ok {
+		return nil, errors.New("malformed wrapped data key")
+	}
+	master, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("malformed wrapped data key: %w", err)
+	}
+	return Open(master, ciphertext, []byte(id))
+}
+
+// Rewrap re-wraps a data key with the active master key, reporting whether
+// anything changed. The data key itself, and so the data, stays the same.
+func (k *Keyring) Rewrap(wrapped string) (string, bool, error) {
+	if wrapped == "" || k == nil || strings.HasPrefix(wrapped, k.active+":") {
+		return wrapped, false, nil
+	}
+
+	dek, err := k.Unwrap(wrapped)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := k.wrap(k.active, dek)
+	if err != nil {
+		return "", false, err
+	}
+	return rewrapped, true, nil
+}
+
+func (k *Keyring) wrap(id string, dek []byte) (string, error) {
+	sealed, err := Seal(k.keys[id], dek, []byte(id))
+	if err != nil {
+		return "", err
+	}
+	return id + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
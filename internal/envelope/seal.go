@@ -0,0 +1,89 @@
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// sealedPrefix marks encrypted values stored in text columns.
+const sealedPrefix = "enc:v1:"
+
+// Seal encrypts plaintext with AES-256-GCM. The random nonce is prepended to
+// the ciphertext. aad binds the ciphertext to its context, e.g. the row and
+// column it is stored in.
+func Seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func Open(key, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ciphertext) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, sealed, aad)
+}
+
+// SealString encrypts a value for a text column. A nil key or empty value is
+// stored as is.
+func SealString(key []byte, value, aad string) (string, error) {
+	if key == nil || value == "" {
+		return value, nil
+	}
+
+	sealed, err := Seal(key, []byte(value), []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenString reverses SealString. Values without the sealed prefix are
+// plaintext written before encryption was enabled and are returned unchanged.
+func OpenString(key []byte, value, aad string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+	if key == nil {
+		return "", ErrNoKeyring
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, sealedPrefix))
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := Open(key, sealed, []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
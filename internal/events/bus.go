@@ -0,0 +1,145 @@
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+type Type string
+
+const (
+	DocumentUploaded Type = "document.uploaded"
+	StatusChanged    Type = "document.status"
+	Progress         Type = "document.progress"
+	DocumentAnalyzed Type = "document.analyzed"
+	DocumentFailed   Type = "document.failed"
+	DocumentRejected Type = "document.rejected"
+)
+
+// Progress stages reported with Progress events.
+const (
+	StageExtraction = "extraction"
+	StageAnalysis   = "analysis"
+)
+
+type Event struct {
+	ID             uint64        `json:"id"`
+	Type           Type          `json:"type"`
+	DocumentID     uuid.UUID     `json:"document_id"`
+	TenantID       string        `json:"tenant_id"`
+	OwnerID        string        `json:"owner_id,omitempty"`
+	Status         string        `json:"status,omitempty"`
+	PreviousStatus string        `json:"previous_status,omitempty"`
+	Progress       *ProgressInfo `json:"progress,omitempty"`
+	Error          string        `json:"error,omitempty"`
+	Data           interface{}   `json:"data,omitempty"`
+	Timestamp      time.Time     `json:"timestamp"`
+}
+
+// ProgressInfo describes how far a document is through a stage, e.g. page 3
+// of 10 during extraction.
+type ProgressInfo struct {
+	Stage   string `json:"stage"`
+	Current int    `json:"current"`
+	Total   int    `json:"total"`
+}
+
+// Filter decides whether a subscriber receives an event. A nil filter
+// receives everything.
+type Filter func(Event) bool
+
+// ForDocument returns a filter matching events for a single document.
+func ForDocument(id uuid.UUID) Filter {
+	return func(e Event) bool {
+		return e.DocumentID == id
+	}
+}
+
+type subscriber struct {
+	ch     chan Event
+	filter Filter
+	// lossless subscribers make Publish wait for room instead of dropping
+	// events; done is closed when they unsubscribe.
+	lossless bool
+	done     chan struct{}
+}
+
+// Bus is an in-process publish/subscribe hub for document events. Publishing
+// does not block on ordinary subscribers: those that fall behind miss events
+// rather than stall the publisher. Lossless subscribers, for consumers that
+// must see every event, hold the publisher up instead.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	seq         atomic.Uint64
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[*subscriber]struct{})}
+}
+
+func (b *Bus) Publish(e Event) {
+	e.ID = b.seq.Add(1)
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if sub.filter != nil && !sub.filter(e) {
+			continue
+		}
+		if sub.lossless {
+			select {
+			case sub.ch <- e:
+			case <-sub.done:
+			}
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+			logger.Warn("Dropping event for slow subscriber", logger.Fields{"type": e.Type, logger.DocumentIDKey: e.DocumentID})
+		}
+	}
+}
+
+// Subscribe registers a subscriber and returns its channel together with a
+// function that unsubscribes and closes the channel.
+func (b *Bus) Subscribe(filter Filter, buffer int) (<-chan Event, func()) {
+	return b.subscribe(&subscriber{ch: make(chan Event, buffer), filter: filter})
+}
+
+// SubscribeLossless is Subscribe for consumers that must not miss events:
+// once its buffer is full, Publish waits until the subscriber reads or
+// unsubscribes. The subscriber must keep reading until it unsubscribes.
+func (b *Bus) SubscribeLossless(filter Filter, buffer int) (<-chan Event, func()) {
+	return b.subscribe(&subscriber{ch: make(chan Event, buffer), filter: filter, lossless: true, done: make(chan struct{})})
+}
+
+func (b *Bus) subscribe(sub *subscriber) (<-chan Event, func()) {
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			// release publishers waiting on a lossless subscriber before
+			// taking the lock they hold
+			if sub.done != nil {
+				close(sub.done)
+			}
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+// Package grpcserver builds the gRPC server with the same request pipeline as
+// the REST API: request IDs and access logs, authentication, per-tenant rate
+// limits, audit attribution and tracing.
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/auth"
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/internal/tracing"
+	"github.com/zjoart/docai/pkg/id"
+	"github.com/zjoart/docai/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// RequestIDKey is the metadata key carrying the request ID both ways.
+	RequestIDKey    = "x-request-id"
+	apiKeyKey       = "x-api-key"
+	maxRequestIDLen = 128
+)
+
+// unauthenticated lists the methods anyone may call, such as health checks.
+var unauthenticated = []string{"/grpc.health.v1.Health/"}
+
+type interceptor struct {
+	auth   *auth.Service
+	quotas *quota.Service
+}
+
+// NewServer returns a gRPC server whose calls are authenticated with
+// authService and rate limited by quotas. Services are registered on it by
+// the caller.
+func NewServer(authService *auth.Service, quotas *quota.Service, opts ...grpc.ServerOption) *grpc.Server {
+	i := &interceptor{auth: authService, quotas: quotas}
+	opts = append([]grpc.ServerOption{
+		grpc.StatsHandler(tracing.GRPCServerHandler()),
+		grpc.ChainUnaryInterceptor(i.unary),
+		grpc.ChainStreamInterceptor(i.stream),
+	}, opts...)
+	return grpc.NewServer(opts...)
+}
+
+func (i *interceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	var resp interface{}
+	err := i.serve(ctx, info.FullMethod, func(ctx context.Context) (err error) {
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (i *interceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return i.serve(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	})
+}
+
+// serve runs a call through the pipeline and writes its access log entry.
+func (i *interceptor) serve(ctx context.Context, method string, call func(context.Context) error) error {
+	started := time.Now()
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	requestID := strings.TrimSpace(first(md, RequestIDKey))
+	if requestID == "" || len(requestID) > maxRequestIDLen {
+		requestID = id.Generate()
+	}
+	grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, requestID))
+	ctx = logger.WithRequestID(ctx, requestID)
+
+	err := i.authorize(ctx, method, md, call)
+
+	code := status.Code(err)
+	fields := logger.Fields{
+		"method":     method,
+		"code":       code.String(),
+		"latency_ms": time.Since(started).Milliseconds(),
+		"remote":     peerAddr(ctx),
+	}
+	log := logger.FromContext(ctx)
+	switch code {
+	case codes.OK:
+		log.Info("gRPC request", fields)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable:
+		log.Error("gRPC request", fields)
+	default:
+		log.Warn("gRPC request", fields)
+	}
+	return err
+}
+
+// authorize authenticates the caller, applies the tenant's request limit and
+// attaches the audit attribution, the way the REST middleware chain does.
+func (i *interceptor) authorize(ctx context.Context, method string, md metadata.MD, call func(context.Context) error) error {
+	for _, prefix := range unauthenticated {
+		if strings.HasPrefix(method, prefix) {
+			return call(ctx)
+		}
+	}
+
+	credential := credentialFromMetadata(md)
+	if credential == "" {
+		return apierror.GRPCError(ctx, apierror.Unauthorized("Authentication required"))
+	}
+	principal, err := i.auth.Authenticate(ctx, credential)
+	var scope tenant.Scope
+	if err == nil {
+		scope, err = principal.TenantScope()
+	}
+	if err != nil {
+		return apierror.GRPCError(ctx, apierror.Unauthorized("Invalid credentials"))
+	}
+
+	ctx = auth.WithPrincipal(ctx, principal)
+	ctx = tenant.WithScope(ctx, scope)
+
+	if i.quotas != nil {
+		if err := i.quotas.AllowRequest(scope.TenantID); err != nil {
+			if exceeded, ok := quota.AsExceeded(err); ok {
+				return apierror.GRPCError(ctx, exceeded.APIError())
+			}
+			// fail open: a quota lookup problem shouldn't take the API down
+			logger.Error("Failed to apply rate limit", logger.Merge(logger.Fields{"tenant_id": scope.TenantID}, logger.WithError(err)))
+		}
+	}
+
+	ctx = audit.WithRequest(ctx, audit.Request{
+		Actor:     principal.Subject,
+		ActorType: principal.Method,
+		IP:        peerIP(ctx),
+		RequestID: logger.RequestIDFromContext(ctx),
+	})
+	return call(ctx)
+}
+
+func credentialFromMetadata(md metadata.MD) string {
+	if key := first(md, apiKeyKey); key != "" {
+		return strings.TrimSpace(key)
+	}
+
+	header := first(md, "authorization")
+	if len(header) > 7 && strings.EqualFold(header[:7], "bearer ") {
+		return strings.TrimSpace(header[7:])
+	}
+	return ""
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// peerIP is the address of the connection; like the REST API it ignores
+// anything the client claims about itself.
+func peerIP(ctx context.Context) string {
+	addr := peerAddr(ctx)
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// serverStream replaces a stream's context with the one built by the
+// interceptor.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
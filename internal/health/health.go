@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// Check probes one dependency and returns nil when it is usable.
+type Check func(ctx context.Context) error
+
+// CheckResult is the outcome of one dependency check.
+type CheckResult struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the body served by /readyz.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Service answers liveness and readiness probes.
+type Service struct {
+	checks   map[string]Check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewService runs each readiness check with the given timeout.
+func NewService(timeout time.Duration) *Service {
+	return &Service{checks: make(map[string]Check), timeout: timeout}
+}
+
+// Register adds a readiness check. It must be called before serving.
+func (s *Service) Register(name string, check Check) {
+	s.checks[name] = check
+}
+
+// Drain makes readiness fail from now on, so the orchestrator stops routing
+// traffic here while in-flight requests finish.
+func (s *Service) Drain() {
+	s.draining.Store(true)
+}
+
+// Ready runs every check concurrently.
+func (s *Service) Ready(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(s.checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range s.checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+			started := time.Now()
+			err := check(ctx)
+
+			result := CheckResult{Status: StatusOK, LatencyMs: time.Since(started).Milliseconds()}
+			if err != nil {
+				result.Status = StatusUnavailable
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if err != nil {
+				report.Status = StatusUnavailable
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	if s.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// Liveness reports that the process is up. It checks no dependencies, so a
+// database outage doesn't get the process restarted.
+func (s *Service) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+}
+
+// Readiness reports whether every dependency is usable and the server is not
+// draining; otherwise it answers 503.
+func (s *Service) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := s.Ready(r.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, report)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
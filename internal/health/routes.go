@@ -0,0 +1,9 @@
+package health
+
+import "github.com/gorilla/mux"
+
+// RegisterRoutes mounts the probes. They must stay outside authentication.
+func RegisterRoutes(r *mux.Router, s *Service) {
+	r.HandleFunc("/healthz", s.Liveness).Methods("GET")
+	r.HandleFunc("/readyz", s.Readiness).Methods("GET")
+}
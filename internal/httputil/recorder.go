@@ -0,0 +1,53 @@
+// Package httputil holds helpers shared by the HTTP middleware.
+package httputil
+
+import "net/http"
+
+// StatusRecorder captures the status and body size of a response while
+// passing flushes through for streaming handlers. Middleware wrap the writer
+// with it and read Status once the handler has returned.
+type StatusRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func NewStatusRecorder(w http.ResponseWriter) *StatusRecorder {
+	return &StatusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+// Status is the first status written, or 200 if the handler wrote none.
+func (r *StatusRecorder) Status() int {
+	return r.status
+}
+
+// Bytes is the number of body bytes written.
+func (r *StatusRecorder) Bytes() int64 {
+	return r.bytes
+}
+
+func (r *StatusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *StatusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += int64(n)
+	return n, err
+}
+
+func (r *StatusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *StatusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
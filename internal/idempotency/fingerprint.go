@@ -0,0 +1,173 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+)
+
+// spoolMemory is how much of a request body is kept in memory before the
+// rest is written to a temporary file.
+const spoolMemory = 1 << 20
+
+var errMalformedBody = errors.New("malformed request body")
+
+// readRequest reads the body of r, up to limit bytes, and fingerprints the
+// request. It returns a copy of the body for the handler, which the caller
+// must close.
+//
+// Clients build a new multipart body with a new boundary on every retry, so
+// forms are fingerprinted by their fields and files rather than their bytes.
+// JSON bodies are fingerprinted in canonical form for the same reason.
+func readRequest(w http.ResponseWriter, r *http.Request, subject string, limit int64) (string, io.ReadCloser, error) {
+	h := sha256.New()
+	writeField(h, r.Method)
+	writeField(h, r.URL.Path)
+	writeField(h, r.URL.RawQuery)
+	writeField(h, subject)
+
+	body := &spool{}
+	src := io.TeeReader(http.MaxBytesReader(w, r.Body, limit), body)
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var err error
+	switch {
+	case mediaType == "multipart/form-data" && params["boundary"] != "":
+		err = hashMultipart(h, multipart.NewReader(src, params["boundary"]))
+		if err == nil {
+			// the epilogue is not part of the form, but the handler should
+			// still see the body as sent
+			_, err = io.Copy(io.Discard, src)
+		}
+	case mediaType == "application/json":
+		err = hashJSON(h, src)
+	default:
+		_, err = io.Copy(h, src)
+	}
+	if err != nil {
+		body.Close()
+		return "", nil, err
+	}
+
+	reader, err := body.reader()
+	if err != nil {
+		body.Close()
+		return "", nil, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), reader, nil
+}
+
+func hashMultipart(h hash.Hash, form *multipart.Reader) error {
+	for {
+		part, err := form.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return wrapMalformed(err)
+		}
+
+		content := sha256.New()
+		if _, err := io.Copy(content, part); err != nil {
+			return wrapMalformed(err)
+		}
+		writeField(h, part.FormName())
+		writeField(h, part.FileName())
+		h.Write(content.Sum(nil))
+	}
+}
+
+func hashJSON(h hash.Hash, src io.Reader) error {
+	raw, err := io.ReadAll(src)
+	if err != nil {
+		return err
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if decoder.Decode(&value) != nil {
+		// the handler reports invalid JSON; fingerprint the bytes as sent
+		h.Write(raw)
+		return nil
+	}
+	canonical, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	h.Write(canonical)
+	return nil
+}
+
+// wrapMalformed keeps body size errors recognisable and marks everything else
+// as a malformed body.
+func wrapMalformed(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", errMalformedBody, err)
+}
+
+// writeField writes a length-prefixed value so that adjacent fields can't
+// run into each other.
+func writeField(h hash.Hash, value string) {
+	fmt.Fprintf(h, "%d:%s", len(value), value)
+}
+
+// spool buffers a body in memory, moving it to a temporary file once it
+// grows past spoolMemory.
+type spool struct {
+	buf  bytes.Buffer
+	file *os.File
+}
+
+func (s *spool) Write(p []byte) (int, error) {
+	if s.file == nil && s.buf.Len()+len(p) > spoolMemory {
+		file, err := os.CreateTemp("", "docai-idempotency-*")
+		if err != nil {
+			return 0, err
+		}
+		s.file = file
+		if _, err := s.buf.WriteTo(file); err != nil {
+			return 0, err
+		}
+	}
+	if s.file != nil {
+		return s.file.Write(p)
+	}
+	return s.buf.Write(p)
+}
+
+// reader returns the buffered body from the start; closing it removes any
+// temporary file.
+func (s *spool) reader() (io.ReadCloser, error) {
+	if s.file == nil {
+		return io.NopCloser(bytes.NewReader(s.buf.Bytes())), nil
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *spool) Read(p []byte) (int, error) {
+	return s.file.Read(p)
+}
+
+func (s *spool) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
@@ -0,0 +1,31 @@
+package idempotency
+
+import "time"
+
+// Record is a request made with an Idempotency-Key and, once it has
+// finished, the response to replay.
+type Record struct {
+	TenantID string `gorm:"primaryKey"`
+	Key      string `gorm:"primaryKey"`
+	// Fingerprint identifies the request the key was first used for.
+	Fingerprint string
+	// StatusCode is 0 while the first request is still running.
+	StatusCode  int
+	ContentType string
+	// DataKey encrypts ResponseBody, as it may carry document text.
+	DataKey      string
+	ResponseBody string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+	// LockedAt is refreshed while the first request is running.
+	LockedAt time.Time
+}
+
+func (Record) TableName() string {
+	return "idempotency_keys"
+}
+
+// Completed reports whether the response has been stored.
+func (r *Record) Completed() bool {
+	return r.StatusCode != 0
+}
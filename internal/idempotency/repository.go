@@ -0,0 +1,135 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"github.com/zjoart/docai/internal/tenant"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	// Reserve claims rec.Key for a new request. If the key is already taken
+	// it returns the existing record instead. Expired records, and records
+	// of unfinished requests whose lock was last refreshed before
+	// staleBefore, are replaced.
+	Reserve(ctx context.Context, rec *Record, staleBefore time.Time) (*Record, error)
+	// Refresh keeps the key of a running request locked.
+	Refresh(ctx context.Context, tenantID, key string, at time.Time) error
+	// Complete stores the response of a reserved request.
+	Complete(ctx context.Context, rec *Record) error
+	// Release frees a key whose request did not finish, so it can be retried.
+	Release(ctx context.Context, tenantID, key string) error
+	// DeleteExpired removes records that expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+
+	// ListDataKeys and UpdateDataKey support re-wrapping data keys after a
+	// master key rotation. Records are listed in key order, starting after
+	// the given one.
+	ListDataKeys(ctx context.Context, after DataKeyRef, limit int) ([]DataKeyRef, error)
+	UpdateDataKey(ctx context.Context, ref DataKeyRef, wrapped string) error
+}
+
+// DataKeyRef is a stored response's wrapped data key.
+type DataKeyRef struct {
+	TenantID string
+	Key      string
+	DataKey  string
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Reserve(ctx context.Context, rec *Record, staleBefore time.Time) (*Record, error) {
+	var existing *Record
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		if !scope.Allows(rec.TenantID, "") {
+			return tenant.ErrOutOfScope
+		}
+
+		err := tx.Where("tenant_id = ? AND key = ?", rec.TenantID, rec.Key).
+			Where("expires_at < ? OR (status_code = 0 AND locked_at < ?)", rec.CreatedAt, staleBefore).
+			Delete(&Record{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+		if result.Error != nil || result.RowsAffected == 1 {
+			return result.Error
+		}
+
+		existing = &Record{}
+		return tx.First(existing, "tenant_id = ? AND key = ?", rec.TenantID, rec.Key).Error
+	})
+	return existing, err
+}
+
+func (r *repository) Refresh(ctx context.Context, tenantID, key string, at time.Time) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx.Model(&Record{})).
+			Where("tenant_id = ? AND key = ? AND status_code = 0", tenantID, key).
+			UpdateColumn("locked_at", at).Error
+	})
+}
+
+func (r *repository) Complete(ctx context.Context, rec *Record) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx.Model(&Record{})).
+			Where("tenant_id = ? AND key = ?", rec.TenantID, rec.Key).
+			Updates(map[string]interface{}{
+				"status_code":   rec.StatusCode,
+				"content_type":  rec.ContentType,
+				"data_key":      rec.DataKey,
+				"response_body": rec.ResponseBody,
+			}).Error
+	})
+}
+
+func (r *repository) Release(ctx context.Context, tenantID, key string) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx).
+			Where("tenant_id = ? AND key = ? AND status_code = 0", tenantID, key).
+			Delete(&Record{}).Error
+	})
+}
+
+func (r *repository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		result := scope.FilterTenant(tx).Where("expires_at < ?", before).Delete(&Record{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+func (r *repository) ListDataKeys(ctx context.Context, after DataKeyRef, limit int) ([]DataKeyRef, error) {
+	var refs []DataKeyRef
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx.Model(&Record{})).
+			Select("tenant_id", "key", "data_key").
+			Where("(tenant_id, key) > (?, ?) AND data_key <> ''", after.TenantID, after.Key).
+			Order("tenant_id, key").
+			Limit(limit).
+			Scan(&refs).Error
+	})
+	return refs, err
+}
+
+// UpdateDataKey swaps a wrapped data key, provided it hasn't changed since it
+// was listed. A record that has since expired or been replaced is left alone;
+// its replacement was written with the active key.
+func (r *repository) UpdateDataKey(ctx context.Context, ref DataKeyRef, wrapped string) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx.Model(&Record{})).
+			Where("tenant_id = ? AND key = ? AND data_key = ?", ref.TenantID, ref.Key, ref.DataKey).
+			UpdateColumn("data_key", wrapped).Error
+	})
+}
@@ -0,0 +1,300 @@
+// Package idempotency lets clients retry unsafe requests. A request sent with
+// an Idempotency-Key header runs once; retries with the same key get the
+// stored response instead of running again.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/auth"
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/httputil"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+const (
+	// Header carries the client's key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set to "true" on replayed responses.
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// maxResponseBytes bounds the responses that are stored; larger ones are
+	// not replayed and the key is released instead.
+	maxResponseBytes = 1 << 20
+	purgeInterval    = 10 * time.Minute
+)
+
+// Config tunes the service; zero values use the defaults.
+type Config struct {
+	// TTL is how long a key and its response are kept. Defaults to 24h.
+	TTL time.Duration
+	// LockTimeout is how long a key stays locked without being refreshed.
+	// The lock is refreshed while its request runs, however long that
+	// takes, so this only bounds how soon a retry may take over the key of
+	// a server that died. Defaults to 1m.
+	LockTimeout time.Duration
+}
+
+type Service struct {
+	repo   Repository
+	keys   *envelope.Keyring
+	config Config
+	now    func() time.Time
+}
+
+// NewService stores responses in repo, encrypted with data keys from keys
+// when it is not nil.
+func NewService(repo Repository, keys *envelope.Keyring, config Config) *Service {
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = time.Minute
+	}
+	return &Service{repo: repo, keys: keys, config: config, now: time.Now}
+}
+
+// Handle makes next idempotent for requests that carry an Idempotency-Key.
+// The first request with a key runs and its response is stored; retries of
+// the same request replay that response, a retry while it is still running
+// gets a 409 and reusing the key for a different request a 422. Server
+// errors and rate limiting are not stored, so the client may retry them.
+//
+// The body is read, up to maxBody(r) bytes, before next runs. It must run
+// after authentication. A nil service passes every request through.
+func (s *Service) Handle(maxBody func(*http.Request) int64, next http.HandlerFunc) http.HandlerFunc {
+	if s == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimSpace(r.Header.Get(Header))
+		scope, ok := tenant.FromContext(r.Context())
+		if key == "" || !ok || scope.IsSystem() {
+			next(w, r)
+			return
+		}
+		if len(key) > maxKeyLength {
+			apierror.Write(w, r, apierror.BadRequest(fmt.Sprintf("%s must be at most %d characters", Header, maxKeyLength)))
+			return
+		}
+
+		var subject string
+		if principal, ok := auth.FromContext(r.Context()); ok {
+			subject = principal.Subject
+		}
+		fingerprint, body, err := readRequest(w, r, subject, maxBody(r))
+		if err != nil {
+			apierror.Write(w, r, bodyError(err))
+			return
+		}
+		defer body.Close()
+
+		now := s.now()
+		rec := &Record{
+			TenantID:    scope.TenantID,
+			Key:         key,
+			Fingerprint: fingerprint,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(s.config.TTL),
+			LockedAt:    now,
+		}
+		existing, err := s.repo.Reserve(r.Context(), rec, now.Add(-s.config.LockTimeout))
+		if err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
+		if existing != nil {
+			s.replay(w, r, existing, fingerprint)
+			return
+		}
+
+		r.Body = body
+		s.run(w, r, rec, next)
+	}
+}
+
+// run serves the first request with a key and stores its response.
+func (s *Service) run(w http.ResponseWriter, r *http.Request, rec *Record, next http.HandlerFunc) {
+	// the client may have gone by the time the response is stored
+	ctx := tenant.Detach(r.Context())
+	log := logger.FromContext(r.Context())
+	recorder := &responseRecorder{StatusRecorder: httputil.NewStatusRecorder(w)}
+
+	stored := false
+	defer func() {
+		if stored {
+			return
+		}
+		if err := s.repo.Release(ctx, rec.TenantID, rec.Key); err != nil {
+			log.Error("Failed to release idempotency key", logger.Merge(logger.Fields{"key": rec.Key}, logger.WithError(err)))
+		}
+	}()
+
+	stopRefresh := s.keepLocked(ctx, rec)
+	next(recorder, r)
+	stopRefresh()
+
+	if status := recorder.Status(); status >= http.StatusInternalServerError || status == http.StatusTooManyRequests || recorder.overflow {
+		return
+	}
+	if err := s.seal(rec, recorder); err != nil {
+		log.Error("Failed to encrypt idempotent response", logger.Merge(logger.Fields{"key": rec.Key}, logger.WithError(err)))
+		return
+	}
+	if err := s.repo.Complete(ctx, rec); err != nil {
+		log.Error("Failed to store idempotent response", logger.Merge(logger.Fields{"key": rec.Key}, logger.WithError(err)))
+		return
+	}
+	stored = true
+}
+
+// keepLocked refreshes the lock on rec's key until the returned function is
+// called, so a slow request is not mistaken for one whose server died.
+func (s *Service) keepLocked(ctx context.Context, rec *Record) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(s.config.LockTimeout / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if err := s.repo.Refresh(ctx, rec.TenantID, rec.Key, s.now()); err != nil {
+				logger.FromContext(ctx).Warn("Failed to refresh idempotency key lock", logger.Merge(logger.Fields{"key": rec.Key}, logger.WithError(err)))
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (s *Service) replay(w http.ResponseWriter, r *http.Request, rec *Record, fingerprint string) {
+	if rec.Fingerprint != fingerprint {
+		apierror.Write(w, r, apierror.New(apierror.CodeIdempotencyKeyReused, Header+" was already used for a different request"))
+		return
+	}
+	if !rec.Completed() {
+		apierror.Write(w, r, &apierror.Error{
+			Code:       apierror.CodeIdempotencyKeyInUse,
+			Message:    "A request with this " + Header + " is still in progress",
+			RetryAfter: time.Second,
+		})
+		return
+	}
+
+	body, err := s.open(rec)
+	if err != nil {
+		apierror.Write(w, r, fmt.Errorf("failed to open idempotent response: %w", err))
+		return
+	}
+	logger.FromContext(r.Context()).Info("Replayed idempotent request", logger.Fields{"key": rec.Key, "status": rec.StatusCode})
+
+	if rec.ContentType != "" {
+		w.Header().Set("Content-Type", rec.ContentType)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(rec.StatusCode)
+	w.Write([]byte(body))
+}
+
+// seal copies the recorded response into rec, encrypted when a keyring is
+// configured.
+func (s *Service) seal(rec *Record, recorder *responseRecorder) error {
+	dek, wrapped, err := s.keys.NewDataKey()
+	if err != nil {
+		return err
+	}
+	body, err := envelope.SealString(dek, recorder.body.String(), sealAAD(rec))
+	if err != nil {
+		return err
+	}
+
+	rec.StatusCode = recorder.Status()
+	rec.ContentType = recorder.Header().Get("Content-Type")
+	rec.DataKey = wrapped
+	rec.ResponseBody = body
+	return nil
+}
+
+func (s *Service) open(rec *Record) (string, error) {
+	dek, err := s.keys.Unwrap(rec.DataKey)
+	if err != nil {
+		return "", err
+	}
+	return envelope.OpenString(dek, rec.ResponseBody, sealAAD(rec))
+}
+
+func sealAAD(rec *Record) string {
+	return rec.TenantID + ":" + rec.Key
+}
+
+func bodyError(err error) error {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return apierror.TooLarge(fmt.Sprintf("Request body too large (max %d bytes)", tooLarge.Limit))
+	case errors.Is(err, errMalformedBody):
+		return apierror.BadRequest("Failed to parse form")
+	default:
+		return apierror.Wrap(apierror.CodeBadRequest, "Failed to read request body", err)
+	}
+}
+
+// Run deletes expired keys until ctx is cancelled.
+func (s *Service) Run(ctx context.Context) {
+	ctx = tenant.WithScope(ctx, tenant.System)
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := s.repo.DeleteExpired(ctx, s.now())
+		if err != nil {
+			logger.Error("Failed to delete expired idempotency keys", logger.WithError(err))
+			continue
+		}
+		if deleted > 0 {
+			logger.Info("Deleted expired idempotency keys", logger.Fields{"count": deleted})
+		}
+	}
+}
+
+// responseRecorder passes a response through while keeping a copy of it.
+type responseRecorder struct {
+	*httputil.StatusRecorder
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if !r.overflow {
+		if r.body.Len()+len(p) > maxResponseBytes {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.StatusRecorder.Write(p)
+}
@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/zjoart/docai/internal/httputil"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+const namespace = "docai"
+
+// statusCountTimeout bounds the database query behind the documents gauge
+// so a slow database cannot stall a scrape.
+const statusCountTimeout = 5 * time.Second
+
+// Metrics implements the measurement interfaces of the document pipeline and
+// the analyzer with Prometheus collectors, and serves them on /metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpDuration       *prometheus.HistogramVec
+	uploadSize         *prometheus.HistogramVec
+	extractionDuration *prometheus.HistogramVec
+	extractionFailures *prometheus.CounterVec
+	llmDuration        *prometheus.HistogramVec
+	llmTokens          *prometheus.CounterVec
+	llmErrors          *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route template, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		uploadSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upload_size_bytes",
+			Help:      "Size of uploaded files by format.",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 8),
+		}, []string{"format"}),
+		extractionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "extraction_duration_seconds",
+			Help:      "Time spent extracting text from uploads by format.",
+			Buckets:   prometheus.ExponentialBuckets(0.005, 3, 9),
+		}, []string{"format"}),
+		extractionFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "extraction_failures_total",
+			Help:      "Uploads whose text could not be extracted, by format.",
+		}, []string{"format"}),
+		llmDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "llm_request_duration_seconds",
+			Help:      "LLM call latency by model.",
+			Buckets:   prometheus.ExponentialBuckets(0.25, 2, 9),
+		}, []string{"model"}),
+		llmTokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "llm_tokens_total",
+			Help:      "Prompt and completion tokens billed by model.",
+		}, []string{"model"}),
+		llmErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "llm_errors_total",
+			Help:      "Failed LLM calls by model.",
+		}, []string{"model"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration,
+		m.uploadSize,
+		m.extractionDuration,
+		m.extractionFailures,
+		m.llmDuration,
+		m.llmTokens,
+		m.llmErrors,
+	)
+	return m
+}
+
+// Handler serves the registered metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) ObserveUpload(format string, sizeBytes int64) {
+	m.uploadSize.WithLabelValues(format).Observe(float64(sizeBytes))
+}
+
+func (m *Metrics) ObserveExtraction(format string, duration time.Duration, err error) {
+	m.extractionDuration.WithLabelValues(format).Observe(duration.Seconds())
+	if err != nil {
+		m.extractionFailures.WithLabelValues(format).Inc()
+	}
+}
+
+func (m *Metrics) ObserveLLMRequest(model string, duration time.Duration, tokens int, err error) {
+	m.llmDuration.WithLabelValues(model).Observe(duration.Seconds())
+	if err != nil {
+		m.llmErrors.WithLabelValues(model).Inc()
+		return
+	}
+	m.llmTokens.WithLabelValues(model).Add(float64(tokens))
+}
+
+// RegisterQueueDepth exports depth as the number of documents waiting for
+// analysis in running batches.
+func (m *Metrics) RegisterQueueDepth(depth func() int64) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "analysis_queue_depth",
+		Help:      "Documents waiting for analysis in running batches.",
+	}, func() float64 {
+		return float64(depth())
+	}))
+}
+
+// RegisterStatusCounts exports the document counts returned by count,
+// queried on every scrape.
+func (m *Metrics) RegisterStatusCounts(count func(ctx context.Context) (map[string]int64, error)) {
+	m.registry.MustRegister(&statusCollector{
+		count: count,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "documents"),
+			"Documents by status.",
+			[]string{"status"}, nil,
+		),
+	})
+}
+
+type statusCollector struct {
+	count func(ctx context.Context) (map[string]int64, error)
+	desc  *prometheus.Desc
+}
+
+func (c *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), statusCountTimeout)
+	defer cancel()
+
+	counts, err := c.count(ctx)
+	if err != nil {
+		logger.Error("Failed to count documents for metrics", logger.WithError(err))
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for status, n := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), status)
+	}
+}
+
+// Middleware observes request latency per route template, so IDs in paths
+// don't explode the label set. It must be installed on the router so the
+// matched route is known.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		rec := httputil.NewStatusRecorder(w)
+		next.ServeHTTP(rec, r)
+
+		route := "unmatched"
+		if current := mux.CurrentRoute(r); current != nil {
+			if tmpl, err := current.GetPathTemplate(); err == nil {
+				route = tmpl
+			}
+		}
+		m.httpDuration.WithLabelValues(route, r.Method, strconv.Itoa(rec.Status())).Observe(time.Since(started).Seconds())
+	})
+}
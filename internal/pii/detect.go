@@ -0,0 +1,192 @@
+package pii
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+type Kind string
+
+const (
+	KindEmail      Kind = "email"
+	KindPhone      Kind = "phone"
+	KindIBAN       Kind = "iban"
+	KindCard       Kind = "card"
+	KindNationalID Kind = "national_id"
+)
+
+// AllKinds lists every detector in the order they run. Longer, checksummed
+// formats go first so that, say, the digits of an IBAN are not taken for a
+// phone number.
+var AllKinds = []Kind{KindIBAN, KindCard, KindNationalID, KindEmail, KindPhone}
+
+// Match is a detected value and its byte offsets in the scanned text.
+type Match struct {
+	Kind  Kind
+	Value string
+	Start int
+	End   int
+}
+
+type detector struct {
+	pattern *regexp.Regexp
+	valid   func(string) bool
+}
+
+var detectors = map[Kind]detector{
+	KindEmail: {
+		pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	},
+	KindIBAN: {
+		pattern: regexp.MustCompile(`\b[A-Z]{2}[0-9]{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		valid:   validIBAN,
+	},
+	KindCard: {
+		pattern: regexp.MustCompile(`\b[0-9](?:[ -]?[0-9]){12,18}\b`),
+		valid:   validLuhn,
+	},
+	KindNationalID: {
+		// US social security numbers and UK national insurance numbers.
+		pattern: regexp.MustCompile(`\b(?:[0-9]{3}-[0-9]{2}-[0-9]{4}|[A-CEGHJ-PR-TW-Z]{2} ?[0-9]{2} ?[0-9]{2} ?[0-9]{2} ?[A-D])\b`),
+		valid:   validNationalID,
+	},
+	KindPhone: {
+		pattern: regexp.MustCompile(`(?:\+|\b)[0-9][0-9 ().-]{6,}[0-9]\b`),
+		valid:   validPhone,
+	},
+}
+
+// Detect returns the PII of the given kinds found in text, in the order the
+// detectors run. Matches never overlap.
+func Detect(text string, kinds []Kind) []Match {
+	var matches []Match
+	taken := func(start, end int) bool {
+		for _, m := range matches {
+			if start < m.End && m.Start < end {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, kind := range orderKinds(kinds) {
+		d := detectors[kind]
+		for _, loc := range d.pattern.FindAllStringIndex(text, -1) {
+			value := text[loc[0]:loc[1]]
+			if d.valid != nil && !d.valid(value) {
+				continue
+			}
+			if taken(loc[0], loc[1]) {
+				continue
+			}
+			matches = append(matches, Match{Kind: kind, Value: value, Start: loc[0], End: loc[1]})
+		}
+	}
+	return matches
+}
+
+func orderKinds(kinds []Kind) []Kind {
+	if len(kinds) == 0 {
+		return AllKinds
+	}
+	wanted := make(map[Kind]bool, len(kinds))
+	for _, k := range kinds {
+		wanted[k] = true
+	}
+	var ordered []Kind
+	for _, k := range AllKinds {
+		if wanted[k] {
+			ordered = append(ordered, k)
+		}
+	}
+	return ordered
+}
+
+func digitsOf(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// validLuhn checks the card number checksum.
+func validLuhn(s string) bool {
+	digits := digitsOf(s)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIBAN checks the ISO 13616 mod-97 checksum.
+func validIBAN(s string) bool {
+	iban := strings.ReplaceAll(s, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	rearranged := iban[4:] + iban[:4]
+	var numeric strings.Builder
+	for _, r := range rearranged {
+		switch {
+		case r >= '0' && r <= '9':
+			numeric.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			numeric.WriteString(big.NewInt(int64(r-'A') + 10).String())
+		default:
+			return false
+		}
+	}
+
+	n, ok := new(big.Int).SetString(numeric.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(n, big.NewInt(97)).Int64() == 1
+}
+
+func validNationalID(s string) bool {
+	if !strings.Contains(s, "-") {
+		// UK NINO; the pattern already excludes the invalid prefix letters
+		prefix := strings.ToUpper(s[:2])
+		switch prefix {
+		case "BG", "GB", "NK", "KN", "TN", "NT", "ZZ":
+			return false
+		}
+		return true
+	}
+
+	// US SSN: area 000, 666 and 900-999, group 00 and serial 0000 are never issued
+	area, group, serial := s[0:3], s[4:6], s[7:11]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+// validPhone accepts 8 to 15 digits, the E.164 range, and rejects things that
+// look like dates.
+func validPhone(s string) bool {
+	digits := digitsOf(s)
+	if len(digits) < 8 || len(digits) > 15 {
+		return false
+	}
+	return !datePattern.MatchString(strings.TrimSpace(s))
+}
+
+var datePattern = regexp.MustCompile(`^[0-9]{1,4}[-./][0-9]{1,2}[-./][0-9]{1,4}$`)
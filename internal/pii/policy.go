@@ -0,0 +1,31 @@
+package pii
+
+import "github.com/zjoart/docai/pkg/logger"
+
+// PolicySource looks up a tenant's policy override. A nil policy means the
+// tenant uses the default.
+type PolicySource interface {
+	FindPIIPolicy(tenantID string) (*Policy, error)
+}
+
+// Redactor applies each tenant's policy to text bound for the LLM.
+type Redactor struct {
+	source   PolicySource
+	defaults Policy
+}
+
+func NewRedactor(source PolicySource, defaults Policy) *Redactor {
+	return &Redactor{source: source, defaults: defaults}
+}
+
+// Redact applies the tenant's policy. If the policy can't be loaded the
+// default is used, since failing open to ModeOff would leak data.
+func (r *Redactor) Redact(tenantID, text string) *Redaction {
+	policy := r.defaults
+	if override, err := r.source.FindPIIPolicy(tenantID); err != nil {
+		logger.Warn("Failed to load PII policy, using default", logger.Merge(logger.Fields{"tenant_id": tenantID}, logger.WithError(err)))
+	} else if override != nil {
+		policy = *override
+	}
+	return Apply(text, policy)
+}
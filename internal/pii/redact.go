@@ -0,0 +1,128 @@
+package pii
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type Mode string
+
+const (
+	// ModeOff sends text to the LLM unchanged.
+	ModeOff Mode = "off"
+	// ModeRedact replaces PII with placeholders and puts the original values
+	// back into the analysis result.
+	ModeRedact Mode = "redact"
+	// ModeStrict replaces PII with placeholders and keeps them in the result,
+	// so the values never leave the original document text.
+	ModeStrict Mode = "strict"
+)
+
+var ErrInvalidPolicy = errors.New("pii mode must be off, redact or strict and kinds one of: email, phone, iban, card, national_id")
+
+// Policy controls what is redacted before text is sent to the LLM. An empty
+// Kinds list means every kind.
+type Policy struct {
+	Mode  Mode   `json:"mode"`
+	Kinds []Kind `json:"kinds,omitempty"`
+}
+
+func (p Policy) Validate() error {
+	switch p.Mode {
+	case ModeOff, ModeRedact, ModeStrict:
+	default:
+		return ErrInvalidPolicy
+	}
+	for _, k := range p.Kinds {
+		if _, ok := detectors[k]; !ok {
+			return ErrInvalidPolicy
+		}
+	}
+	return nil
+}
+
+// Redaction is a copy of a text with PII swapped for placeholder tokens such
+// as [EMAIL_1]. The same value always maps to the same token.
+type Redaction struct {
+	Text    string
+	Policy  Policy
+	values  map[string]string
+	counts  map[Kind]int
+	restore *strings.Replacer
+}
+
+// Apply redacts text according to the policy.
+func Apply(text string, policy Policy) *Redaction {
+	r := &Redaction{Text: text, Policy: policy, values: map[string]string{}, counts: map[Kind]int{}}
+	if policy.Mode == ModeOff {
+		return r
+	}
+
+	matches := Detect(text, policy.Kinds)
+	if len(matches) == 0 {
+		return r
+	}
+
+	tokens := map[string]string{}
+	var b strings.Builder
+	last := 0
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	for _, m := range matches {
+		token, ok := tokens[m.Value]
+		if !ok {
+			r.counts[m.Kind]++
+			token = fmt.Sprintf("[%s_%d]", strings.ToUpper(string(m.Kind)), r.counts[m.Kind])
+			tokens[m.Value] = token
+			r.values[token] = m.Value
+		}
+		b.WriteString(text[last:m.Start])
+		b.WriteString(token)
+		last = m.End
+	}
+	b.WriteString(text[last:])
+	r.Text = b.String()
+
+	pairs := make([]string, 0, len(r.values)*2)
+	for token, value := range r.values {
+		pairs = append(pairs, token, value)
+	}
+	r.restore = strings.NewReplacer(pairs...)
+
+	return r
+}
+
+// Counts reports how many distinct values of each kind were redacted.
+func (r *Redaction) Counts() map[Kind]int {
+	return r.counts
+}
+
+// Restore puts the original values back in place of their tokens. In strict
+// mode the tokens are left as they are.
+func (r *Redaction) Restore(s string) string {
+	if r.restore == nil || r.Policy.Mode != ModeRedact {
+		return s
+	}
+	return r.restore.Replace(s)
+}
+
+// RestoreValue applies Restore to every string inside a decoded JSON value.
+func (r *Redaction) RestoreValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string:
+		return r.Restore(t)
+	case map[string]interface{}:
+		for k, inner := range t {
+			t[k] = r.RestoreValue(inner)
+		}
+		return t
+	case []interface{}:
+		for i, inner := range t {
+			t[i] = r.RestoreValue(inner)
+		}
+		return t
+	default:
+		return v
+	}
+}
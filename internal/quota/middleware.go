@@ -0,0 +1,44 @@
+package quota
+
+import (
+	"net/http"
+
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+// APIError describes the refusal to clients as a 429 naming the limit, with
+// a Retry-After.
+func (e *ExceededError) APIError() *apierror.Error {
+	return &apierror.Error{
+		Code:       apierror.CodeQuotaExceeded,
+		Message:    "Quota exceeded: " + e.Limit,
+		Err:        e,
+		RetryAfter: e.RetryAfter,
+		Details:    map[string]interface{}{"limit": e.Limit},
+	}
+}
+
+// Middleware applies the per-minute request limit of the tenant in the
+// request context. It must run after authentication.
+func (s *Service) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := tenant.FromContext(r.Context())
+		if !ok || scope.IsSystem() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := s.AllowRequest(scope.TenantID); err != nil {
+			if exceeded, ok := AsExceeded(err); ok {
+				apierror.Write(w, r, exceeded.APIError())
+				return
+			}
+			// fail open: a quota lookup problem shouldn't take the API down
+			logger.Error("Failed to apply rate limit", logger.Merge(logger.Fields{"tenant_id": scope.TenantID}, logger.WithError(err)))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
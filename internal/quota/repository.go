@@ -0,0 +1,74 @@
+package quota
+
+import (
+	"context"
+	"time"
+
+	"github.com/zjoart/docai/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// Usage metrics tracked in tenant_usage.
+const (
+	MetricAnalyses = "analyses"
+	MetricTokens   = "tokens"
+)
+
+// Repository tracks usage counters. tenant_usage is not covered by row-level
+// security, so every method filters on the tenant it is given.
+type Repository interface {
+	// Increment adds delta to a counter unless that would take it above
+	// limit, reporting whether the increment was applied. A limit of zero
+	// or less is unlimited.
+	Increment(tenantID, metric string, period time.Time, delta, limit int64) (bool, error)
+	Get(tenantID, metric string, period time.Time) (int64, error)
+	StorageUsed(ctx context.Context, tenantID string) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Increment(tenantID, metric string, period time.Time, delta, limit int64) (bool, error) {
+	if limit > 0 && delta > limit {
+		return false, nil
+	}
+
+	var amounts []int64
+	err := r.db.Raw(`
+		INSERT INTO tenant_usage (tenant_id, metric, period, amount, updated_at)
+		VALUES (?, ?, ?, ?, NOW())
+		ON CONFLICT (tenant_id, metric, period) DO UPDATE
+			SET amount = tenant_usage.amount + EXCLUDED.amount, updated_at = NOW()
+			WHERE ? <= 0 OR tenant_usage.amount + EXCLUDED.amount <= ?
+		RETURNING amount`,
+		tenantID, metric, period, delta, limit, limit,
+	).Scan(&amounts).Error
+	if err != nil {
+		return false, err
+	}
+	return len(amounts) > 0, nil
+}
+
+func (r *repository) Get(tenantID, metric string, period time.Time) (int64, error) {
+	var amount int64
+	err := r.db.Raw(
+		"SELECT COALESCE(SUM(amount), 0) FROM tenant_usage WHERE tenant_id = ? AND metric = ? AND period = ?",
+		tenantID, metric, period,
+	).Scan(&amount).Error
+	return amount, err
+}
+
+// StorageUsed sums the size of a tenant's documents. It runs in the tenant
+// scope of ctx because documents are protected by row-level security.
+func (r *repository) StorageUsed(ctx context.Context, tenantID string) (int64, error) {
+	var used int64
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return tx.Raw("SELECT COALESCE(SUM(size_bytes), 0) FROM documents WHERE tenant_id = ?", tenantID).Scan(&used).Error
+	})
+	return used, err
+}
@@ -0,0 +1,237 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+	"golang.org/x/time/rate"
+)
+
+// Limit names reported in ExceededError.
+const (
+	LimitRequests = "requests_per_minute"
+	LimitAnalyses = "daily_analyses"
+	LimitStorage  = "storage_bytes"
+	LimitTokens   = "monthly_tokens"
+)
+
+const (
+	limitsCacheTTL = time.Minute
+	// storageRetryAfter is advertised when the storage quota is full. Space
+	// is not freed on a schedule, so this only paces retries.
+	storageRetryAfter = time.Hour
+)
+
+// ExceededError is returned when a request would go over a tenant quota.
+type ExceededError struct {
+	Limit      string
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s", e.Limit)
+}
+
+// AsExceeded reports whether err is, or wraps, an ExceededError.
+func AsExceeded(err error) (*ExceededError, bool) {
+	var exceeded *ExceededError
+	ok := errors.As(err, &exceeded)
+	return exceeded, ok
+}
+
+// Limits are the effective quotas of a tenant. Zero means unlimited.
+type Limits struct {
+	RequestsPerMinute int
+	DailyAnalyses     int64
+	StorageBytes      int64
+	MonthlyTokens     int64
+}
+
+type cachedLimits struct {
+	limits  Limits
+	expires time.Time
+}
+
+type tenantLimiter struct {
+	rpm     int
+	limiter *rate.Limiter
+}
+
+type Service struct {
+	repo     Repository
+	tenants  tenant.Repository
+	defaults Limits
+	now      func() time.Time
+
+	mu       sync.Mutex
+	cache    map[string]cachedLimits
+	limiters map[string]*tenantLimiter
+}
+
+func NewService(repo Repository, tenants tenant.Repository, defaults Limits) *Service {
+	return &Service{
+		repo:     repo,
+		tenants:  tenants,
+		defaults: defaults,
+		now:      time.Now,
+		cache:    make(map[string]cachedLimits),
+		limiters: make(map[string]*tenantLimiter),
+	}
+}
+
+// LimitsFor returns the tenant's overrides merged over the defaults. Results
+// are cached briefly so the rate limiter doesn't hit the database on every
+// request.
+func (s *Service) LimitsFor(tenantID string) (Limits, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[tenantID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.limits, nil
+	}
+
+	t, err := s.tenants.FindByID(tenantID)
+	if err != nil {
+		return Limits{}, err
+	}
+
+	limits := s.defaults
+	if t.Limits.RequestsPerMinute != nil {
+		limits.RequestsPerMinute = *t.Limits.RequestsPerMinute
+	}
+	if t.Limits.DailyAnalyses != nil {
+		limits.DailyAnalyses = *t.Limits.DailyAnalyses
+	}
+	if t.Limits.StorageBytes != nil {
+		limits.StorageBytes = *t.Limits.StorageBytes
+	}
+	if t.Limits.MonthlyTokens != nil {
+		limits.MonthlyTokens = *t.Limits.MonthlyTokens
+	}
+
+	s.mu.Lock()
+	s.cache[tenantID] = cachedLimits{limits: limits, expires: now.Add(limitsCacheTTL)}
+	s.mu.Unlock()
+
+	return limits, nil
+}
+
+// Invalidate drops cached limits after a tenant's overrides change.
+func (s *Service) Invalidate(tenantID string) {
+	s.mu.Lock()
+	delete(s.cache, tenantID)
+	s.mu.Unlock()
+}
+
+// AllowRequest applies the per-minute request limit of a tenant.
+func (s *Service) AllowRequest(tenantID string) error {
+	limits, err := s.LimitsFor(tenantID)
+	if err != nil {
+		return err
+	}
+	if limits.RequestsPerMinute <= 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	tl, ok := s.limiters[tenantID]
+	if !ok || tl.rpm != limits.RequestsPerMinute {
+		tl = &tenantLimiter{
+			rpm:     limits.RequestsPerMinute,
+			limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(limits.RequestsPerMinute)), limits.RequestsPerMinute),
+		}
+		s.limiters[tenantID] = tl
+	}
+	s.mu.Unlock()
+
+	now := s.now()
+	reservation := tl.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return &ExceededError{Limit: LimitRequests, RetryAfter: delay}
+	}
+	return nil
+}
+
+// ReserveAnalysis counts an analysis against the daily quota and checks that
+// the monthly token budget has not been spent. Call ReleaseAnalysis if the
+// analysis does not go ahead after all.
+func (s *Service) ReserveAnalysis(ctx context.Context, tenantID string) error {
+	limits, err := s.LimitsFor(tenantID)
+	if err != nil {
+		return err
+	}
+	now := s.now().UTC()
+
+	if limits.MonthlyTokens > 0 {
+		used, err := s.repo.Get(tenantID, MetricTokens, monthStart(now))
+		if err != nil {
+			return err
+		}
+		if used >= limits.MonthlyTokens {
+			return &ExceededError{Limit: LimitTokens, RetryAfter: monthStart(now).AddDate(0, 1, 0).Sub(now)}
+		}
+	}
+
+	ok, err := s.repo.Increment(tenantID, MetricAnalyses, dayStart(now), 1, limits.DailyAnalyses)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &ExceededError{Limit: LimitAnalyses, RetryAfter: dayStart(now).AddDate(0, 0, 1).Sub(now)}
+	}
+	return nil
+}
+
+func (s *Service) ReleaseAnalysis(ctx context.Context, tenantID string) {
+	if _, err := s.repo.Increment(tenantID, MetricAnalyses, dayStart(s.now().UTC()), -1, 0); err != nil {
+		logger.Warn("Failed to release analysis quota", logger.Merge(logger.Fields{"tenant_id": tenantID}, logger.WithError(err)))
+	}
+}
+
+// RecordTokens adds LLM tokens to the monthly budget. The budget is checked
+// before each analysis, so the analysis that crosses it still completes.
+func (s *Service) RecordTokens(ctx context.Context, tenantID string, tokens int) {
+	if tokens <= 0 {
+		return
+	}
+	if _, err := s.repo.Increment(tenantID, MetricTokens, monthStart(s.now().UTC()), int64(tokens), 0); err != nil {
+		logger.Error("Failed to record token usage", logger.Merge(logger.Fields{"tenant_id": tenantID, "tokens": tokens}, logger.WithError(err)))
+	}
+}
+
+// CheckStorage reports whether size more bytes fit in the tenant's storage
+// quota.
+func (s *Service) CheckStorage(ctx context.Context, tenantID string, size int64) error {
+	limits, err := s.LimitsFor(tenantID)
+	if err != nil {
+		return err
+	}
+	if limits.StorageBytes <= 0 {
+		return nil
+	}
+
+	used, err := s.repo.StorageUsed(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if used+size > limits.StorageBytes {
+		return &ExceededError{Limit: LimitStorage, RetryAfter: storageRetryAfter}
+	}
+	return nil
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,55 @@
+package requestlog
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/zjoart/docai/internal/httputil"
+	"github.com/zjoart/docai/pkg/id"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	maxRequestIDLen = 128
+)
+
+// Middleware assigns every request an ID, keeping a well-formed X-Request-ID
+// sent by the client, echoes it in the response and stores it in the context
+// for logger.FromContext. When the handler returns it writes an access log
+// entry with the status, bytes written and latency. It should wrap every
+// other middleware.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+
+		requestID := strings.TrimSpace(r.Header.Get(RequestIDHeader))
+		if requestID == "" || len(requestID) > maxRequestIDLen {
+			requestID = id.Generate()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		ctx := logger.WithRequestID(r.Context(), requestID)
+		rec := httputil.NewStatusRecorder(w)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		fields := logger.Fields{
+			"method":     r.Method,
+			"path":       r.URL.Path,
+			"status":     rec.Status(),
+			"bytes":      rec.Bytes(),
+			"latency_ms": time.Since(started).Milliseconds(),
+			"remote":     r.RemoteAddr,
+		}
+		log := logger.FromContext(ctx)
+		switch {
+		case rec.Status() >= 500:
+			log.Error("HTTP request", fields)
+		case rec.Status() >= 400:
+			log.Warn("HTTP request", fields)
+		default:
+			log.Info("HTTP request", fields)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunkSize stays well below clamd's default StreamMaxLength chunking.
+const clamdChunkSize = 64 * 1024
+
+// Clamd scans files with a ClamAV daemon over TCP using the INSTREAM command.
+type Clamd struct {
+	addr    string
+	timeout time.Duration
+}
+
+func NewClamd(addr string, timeout time.Duration) *Clamd {
+	return &Clamd{addr: addr, timeout: timeout}
+}
+
+func (c *Clamd) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return Result{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	if err := c.stream(conn, r); err != nil {
+		return Result{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return Result{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return parseClamdReply(reply)
+}
+
+// stream sends the file as length-prefixed chunks, ending with an empty one.
+func (c *Clamd) stream(conn net.Conn, r io.Reader) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+
+	buf := make([]byte, clamdChunkSize)
+	size := make([]byte, 4)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, werr := conn.Write(size); werr != nil {
+				return werr
+			}
+			if _, werr := conn.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// parseClamdReply reads replies such as "stream: OK" and
+// "stream: Eicar-Test-Signature FOUND".
+func parseClamdReply(reply string) (Result, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	_, verdict, ok := strings.Cut(reply, ": ")
+	if !ok {
+		return Result{}, fmt.Errorf("%w: unexpected reply %q", ErrUnavailable, reply)
+	}
+
+	switch {
+	case verdict == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(verdict, " FOUND")}, nil
+	default:
+		return Result{}, fmt.Errorf("%w: %s", ErrUnavailable, verdict)
+	}
+}
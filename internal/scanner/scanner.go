@@ -0,0 +1,23 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrUnavailable wraps failures to reach or talk to the scanner. Uploads are
+// refused rather than stored unscanned.
+var ErrUnavailable = errors.New("malware scanner unavailable")
+
+// Result is the verdict on a scanned file.
+type Result struct {
+	Infected bool
+	// Signature names the detected malware when Infected is set.
+	Signature string
+}
+
+// Scanner checks file content for malware.
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
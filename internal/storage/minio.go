@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -8,13 +9,23 @@ import (
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/tracing"
 	"github.com/zjoart/docai/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// encryptionMetaKey is the user metadata that marks encrypted objects. MinIO
+// returns user metadata keys canonicalised, hence the casing.
+const (
+	encryptionMetaKey = "Docai-Encryption"
+	encryptionV1      = "v1"
 )
 
 type Client struct {
 	minioClient *minio.Client
 	bucketName  string
-	endpoint    string
 }
 
 func NewMinioClient(endpoint, accessKey, secretKey, bucketName string) (*Client, error) {
@@ -33,7 +44,6 @@ func NewMinioClient(endpoint, accessKey, secretKey, bucketName string) (*Client,
 	client := &Client{
 		minioClient: minioClient,
 		bucketName:  bucketName,
-		endpoint:    endpoint,
 	}
 
 	if err := client.EnsureBucket(context.Background()); err != nil {
@@ -43,7 +53,10 @@ func NewMinioClient(endpoint, accessKey, secretKey, bucketName string) (*Client,
 	return client, nil
 }
 
-func (c *Client) EnsureBucket(ctx context.Context) error {
+func (c *Client) EnsureBucket(ctx context.Context) (err error) {
+	ctx, span := c.startSpan(ctx, "storage.EnsureBucket")
+	defer func() { tracing.End(span, err) }()
+
 	exists, err := c.minioClient.BucketExists(ctx, c.bucketName)
 	if err == nil && exists {
 		logger.Debug("Bucket exists", logger.Fields{"bucket": c.bucketName})
@@ -62,19 +75,56 @@ func (c *Client) EnsureBucket(ctx context.Context) error {
 	return nil
 }
 
-func (c *Client) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (string, error) {
-	_, err := c.minioClient.PutObject(ctx, c.bucketName, objectName, reader, size, minio.PutObjectOptions{
-		ContentType: contentType,
-	})
+// CheckBucket reports whether the bucket is reachable and exists. Unlike
+// EnsureBucket it never creates it, so it is safe for readiness probes.
+func (c *Client) CheckBucket(ctx context.Context) error {
+	exists, err := c.minioClient.BucketExists(ctx, c.bucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %q does not exist", c.bucketName)
+	}
+	return nil
+}
+
+// UploadFile stores an object. When dataKey is set the content is encrypted
+// with it first and the object is marked as encrypted.
+func (c *Client) UploadFile(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string, dataKey []byte) (err error) {
+	ctx, span := c.startSpan(ctx, "storage.UploadFile",
+		attribute.String("storage.object", objectName),
+		attribute.Int64("storage.size_bytes", size),
+		attribute.Bool("storage.encrypted", dataKey != nil),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	opts := minio.PutObjectOptions{ContentType: contentType}
+
+	if dataKey != nil {
+		plaintext, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		sealed, err := envelope.Seal(dataKey, plaintext, nil)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt object: %w", err)
+		}
+		reader, size = bytes.NewReader(sealed), int64(len(sealed))
+		opts.UserMetadata = map[string]string{encryptionMetaKey: encryptionV1}
+	}
+
+	_, err = c.minioClient.PutObject(ctx, c.bucketName, objectName, reader, size, opts)
 	if err != nil {
 		logger.Error("Failed to upload file", logger.Merge(logger.Fields{"bucket": c.bucketName, "object": objectName}, logger.WithError(err)))
-		return "", err
+		return err
 	}
 
-	return fmt.Sprintf("http://%s/%s/%s", c.endpoint, c.bucketName, objectName), nil
+	return nil
 }
 
-func (c *Client) GetFileURL(ctx context.Context, objectName string) (string, error) {
+func (c *Client) GetFileURL(ctx context.Context, objectName string) (_ string, err error) {
+	ctx, span := c.startSpan(ctx, "storage.GetFileURL", attribute.String("storage.object", objectName))
+	defer func() { tracing.End(span, err) }()
 
 	reqParams := make(map[string][]string)
 	presignedURL, err := c.minioClient.PresignedGetObject(ctx, c.bucketName, objectName, time.Hour, reqParams)
@@ -85,10 +135,49 @@ func (c *Client) GetFileURL(ctx context.Context, objectName string) (string, err
 	return presignedURL.String(), nil
 }
 
-func (c *Client) GetFileContent(ctx context.Context, objectName string) (io.ReadCloser, error) {
-	return c.minioClient.GetObject(ctx, c.bucketName, objectName, minio.GetObjectOptions{})
+// GetFileContent returns an object's content, decrypting it with dataKey if
+// it was stored encrypted. Objects written before encryption was enabled are
+// returned as they are.
+func (c *Client) GetFileContent(ctx context.Context, objectName string, dataKey []byte) (_ io.ReadCloser, err error) {
+	ctx, span := c.startSpan(ctx, "storage.GetFileContent", attribute.String("storage.object", objectName))
+	defer func() { tracing.End(span, err) }()
+
+	obj, err := c.minioClient.GetObject(ctx, c.bucketName, objectName, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, err
+	}
+	if info.UserMetadata[encryptionMetaKey] != encryptionV1 {
+		return obj, nil
+	}
+	defer obj.Close()
+
+	if dataKey == nil {
+		return nil, envelope.ErrNoKeyring
+	}
+	sealed, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := envelope.Open(dataKey, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt object: %w", err)
+	}
+	return io.NopCloser(bytes.NewReader(plaintext)), nil
 }
 
-func (c *Client) DeleteFile(ctx context.Context, objectName string) error {
+func (c *Client) DeleteFile(ctx context.Context, objectName string) (err error) {
+	ctx, span := c.startSpan(ctx, "storage.DeleteFile", attribute.String("storage.object", objectName))
+	defer func() { tracing.End(span, err) }()
+
 	return c.minioClient.RemoveObject(ctx, c.bucketName, objectName, minio.RemoveObjectOptions{})
 }
+
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name, append(attrs, attribute.String("storage.bucket", c.bucketName))...)
+}
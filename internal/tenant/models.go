@@ -0,0 +1,27 @@
+package tenant
+
+import (
+	"time"
+
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/upload"
+)
+
+type Tenant struct {
+	ID           string         `gorm:"primary_key" json:"id"`
+	Name         string         `json:"name"`
+	Limits       Limits         `gorm:"embedded;embeddedPrefix:limit_" json:"limits"`
+	PIIPolicy    *pii.Policy    `gorm:"serializer:json" json:"pii_policy,omitempty"`
+	UploadPolicy *upload.Policy `gorm:"serializer:json" json:"upload_policy,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+// Limits override the configured quota defaults for a tenant. A nil field
+// falls back to the default; zero means unlimited.
+type Limits struct {
+	RequestsPerMinute *int   `json:"requests_per_minute,omitempty"`
+	DailyAnalyses     *int64 `json:"daily_analyses,omitempty"`
+	StorageBytes      *int64 `json:"storage_bytes,omitempty"`
+	MonthlyTokens     *int64 `json:"monthly_tokens,omitempty"`
+}
@@ -0,0 +1,131 @@
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/upload"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(t *Tenant) error
+	FindByID(id string) (*Tenant, error)
+	List() ([]Tenant, error)
+	UpdateLimits(id string, limits Limits) error
+	FindPIIPolicy(id string) (*pii.Policy, error)
+	UpdatePIIPolicy(id string, policy *pii.Policy) error
+	FindUploadPolicy(id string) (*upload.Policy, error)
+	UpdateUploadPolicy(id string, policy *upload.Policy) error
+	IsNotFoundError(err error) bool
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(t *Tenant) error {
+	return r.db.Create(t).Error
+}
+
+func (r *repository) FindByID(id string) (*Tenant, error) {
+	var t Tenant
+	err := r.db.First(&t, "id = ?", id).Error
+	return &t, err
+}
+
+func (r *repository) List() ([]Tenant, error) {
+	var tenants []Tenant
+	err := r.db.Order("id").Find(&tenants).Error
+	return tenants, err
+}
+
+// UpdateLimits replaces every limit override, so omitted limits revert to
+// the defaults.
+func (r *repository) UpdateLimits(id string, limits Limits) error {
+	result := r.db.Model(&Tenant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"limit_requests_per_minute": limits.RequestsPerMinute,
+		"limit_daily_analyses":      limits.DailyAnalyses,
+		"limit_storage_bytes":       limits.StorageBytes,
+		"limit_monthly_tokens":      limits.MonthlyTokens,
+		"updated_at":                time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) FindPIIPolicy(id string) (*pii.Policy, error) {
+	t, err := r.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return t.PIIPolicy, nil
+}
+
+// UpdatePIIPolicy sets the tenant's policy; nil reverts to the default.
+func (r *repository) UpdatePIIPolicy(id string, policy *pii.Policy) error {
+	result := r.db.Model(&Tenant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"pii_policy": gorm.Expr("?::jsonb", policyJSON(policy)),
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) FindUploadPolicy(id string) (*upload.Policy, error) {
+	t, err := r.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return t.UploadPolicy, nil
+}
+
+// UpdateUploadPolicy sets the tenant's policy; nil reverts to the default.
+func (r *repository) UpdateUploadPolicy(id string, policy *upload.Policy) error {
+	var value interface{}
+	if policy != nil {
+		b, _ := json.Marshal(policy)
+		value = string(b)
+	}
+
+	result := r.db.Model(&Tenant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"upload_policy": gorm.Expr("?::jsonb", value),
+		"updated_at":    time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func policyJSON(policy *pii.Policy) interface{} {
+	if policy == nil {
+		return nil
+	}
+	b, _ := json.Marshal(policy)
+	return string(b)
+}
+
+func (r *repository) IsNotFoundError(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
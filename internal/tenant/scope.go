@@ -0,0 +1,97 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTenant owns everything that existed before tenants were introduced.
+const DefaultTenant = "default"
+
+// systemTenant is the app.tenant_id value that lets background jobs see rows
+// of every tenant through the row-level security policies.
+const systemTenant = "*"
+
+var (
+	ErrNoScope    = errors.New("no tenant scope in context")
+	ErrOutOfScope = errors.New("row is outside the tenant scope")
+)
+
+// Scope identifies whose data a request may touch.
+type Scope struct {
+	TenantID string
+	// OwnerID, when set, further restricts access to rows created by that owner.
+	OwnerID string
+}
+
+// System is the scope used by background jobs that work across tenants,
+// such as the webhook dispatcher.
+var System = Scope{TenantID: systemTenant}
+
+func (s Scope) IsSystem() bool {
+	return s.TenantID == systemTenant
+}
+
+// Allows reports whether a row owned by tenantID/ownerID is visible in this scope.
+func (s Scope) Allows(tenantID, ownerID string) bool {
+	if s.IsSystem() {
+		return true
+	}
+	return s.TenantID == tenantID && (s.OwnerID == "" || s.OwnerID == ownerID)
+}
+
+// Filter restricts a query on a table with tenant_id and owner_id columns.
+func (s Scope) Filter(tx *gorm.DB) *gorm.DB {
+	tx = s.FilterTenant(tx)
+	if !s.IsSystem() && s.OwnerID != "" {
+		tx = tx.Where("owner_id = ?", s.OwnerID)
+	}
+	return tx
+}
+
+// FilterTenant restricts a query on a table with a tenant_id column.
+func (s Scope) FilterTenant(tx *gorm.DB) *gorm.DB {
+	if s.IsSystem() {
+		return tx
+	}
+	return tx.Where("tenant_id = ?", s.TenantID)
+}
+
+type contextKey struct{}
+
+func WithScope(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+func FromContext(ctx context.Context) (Scope, bool) {
+	s, ok := ctx.Value(contextKey{}).(Scope)
+	return s, ok && s.TenantID != ""
+}
+
+// Detach returns a background context carrying the scope of ctx, for work
+// that outlives the request that started it.
+func Detach(ctx context.Context) context.Context {
+	detached := context.Background()
+	if s, ok := FromContext(ctx); ok {
+		detached = WithScope(detached, s)
+	}
+	return detached
+}
+
+// Transaction runs fn in a transaction with app.tenant_id set to the scope's
+// tenant, so Postgres row-level security backs up the explicit filters.
+func Transaction(ctx context.Context, db *gorm.DB, fn func(tx *gorm.DB, scope Scope) error) error {
+	scope, ok := FromContext(ctx)
+	if !ok {
+		return ErrNoScope
+	}
+
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT set_config('app.tenant_id', ?, true)", scope.TenantID).Error; err != nil {
+			return err
+		}
+		return fn(tx, scope)
+	})
+}
@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// InstrumentGORM adds a client span around every statement run through db.
+// Statements are recorded with placeholders only, never with their values.
+func InstrumentGORM(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", startQuery("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endQuery),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", startQuery("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endQuery),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", startQuery("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endQuery),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", startQuery("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endQuery),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", startQuery("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endQuery),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", startQuery("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endQuery),
+	)
+}
+
+func startQuery(op string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx, span := Start(tx.Statement.Context, "gorm."+op,
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", op),
+		)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func endQuery(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("db.statement", tx.Statement.SQL.String()),
+		attribute.String("db.sql.table", tx.Statement.Table),
+		attribute.Int64("db.rows_affected", tx.RowsAffected),
+	)
+
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// a miss is an answer, not a failure
+		err = nil
+	}
+	End(span, err)
+}
@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/pkg/logger"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/stats"
+)
+
+// Handler starts a server span for every request, continuing any trace the
+// caller propagated with a traceparent header.
+func Handler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method
+	}))
+}
+
+// RouteMiddleware names the server span after the matched route template and
+// tags it with the request ID. It must be installed on the router.
+func RouteMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span := trace.SpanFromContext(r.Context())
+		if current := mux.CurrentRoute(r); current != nil {
+			if tmpl, err := current.GetPathTemplate(); err == nil {
+				span.SetName(r.Method + " " + tmpl)
+				span.SetAttributes(attribute.String("http.route", tmpl))
+			}
+		}
+		if id := logger.RequestIDFromContext(r.Context()); id != "" {
+			span.SetAttributes(attribute.String("request.id", id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Transport traces outgoing requests and propagates the trace context.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base)
+}
+
+// GRPCServerHandler starts a server span for every gRPC call, continuing any
+// trace the caller propagated in its metadata.
+func GRPCServerHandler() stats.Handler {
+	return otelgrpc.NewServerHandler()
+}
@@ -0,0 +1,182 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+const (
+	TypePDF  = "application/pdf"
+	TypeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	TypeText = "text/plain"
+)
+
+// extensions maps the file extensions we can extract text from to their type.
+var extensions = map[string]string{
+	".pdf":  TypePDF,
+	".docx": TypeDOCX,
+	".txt":  TypeText,
+}
+
+var (
+	ErrInvalidPolicy  = errors.New("upload policy needs a positive max_bytes, allowed_types from application/pdf, application/vnd.openxmlformats-officedocument.wordprocessingml.document and text/plain, and non-negative limits")
+	ErrTypeNotAllowed = errors.New("file type not allowed")
+	ErrTooManyPages   = errors.New("document has too many pages")
+)
+
+// TooLargeError is returned for uploads over the size limit of their type.
+type TooLargeError struct {
+	Limit int64
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("file too large (max %d bytes)", e.Limit)
+}
+
+// Policy limits what may be uploaded.
+type Policy struct {
+	// MaxBytes caps every upload; MaxBytesByType overrides it per type.
+	MaxBytes       int64            `json:"max_bytes"`
+	MaxBytesByType map[string]int64 `json:"max_bytes_by_type,omitempty"`
+	// AllowedTypes are the accepted MIME types.
+	AllowedTypes []string `json:"allowed_types"`
+	// MaxPages caps the pages of a PDF; 0 means no limit.
+	MaxPages int `json:"max_pages,omitempty"`
+}
+
+// Default is the policy used when none is configured.
+func Default() Policy {
+	return Policy{
+		MaxBytes:     5 << 20,
+		AllowedTypes: []string{TypePDF, TypeDOCX, TypeText},
+	}
+}
+
+func (p Policy) Validate() error {
+	if p.MaxBytes <= 0 || p.MaxPages < 0 || len(p.AllowedTypes) == 0 {
+		return ErrInvalidPolicy
+	}
+	for _, t := range p.AllowedTypes {
+		if !supported(t) {
+			return ErrInvalidPolicy
+		}
+	}
+	for t, max := range p.MaxBytesByType {
+		if !supported(t) || max <= 0 {
+			return ErrInvalidPolicy
+		}
+	}
+	return nil
+}
+
+// TypeOf returns the MIME type of a file by its extension, or false if its
+// text can't be extracted.
+func TypeOf(filename string) (string, bool) {
+	t, ok := extensions[strings.ToLower(filepath.Ext(filename))]
+	return t, ok
+}
+
+// Allows reports whether a type may be uploaded.
+func (p Policy) Allows(mimeType string) bool {
+	for _, t := range p.AllowedTypes {
+		if t == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxBytesFor is the size limit of a type.
+func (p Policy) MaxBytesFor(mimeType string) int64 {
+	if max, ok := p.MaxBytesByType[mimeType]; ok {
+		return max
+	}
+	return p.MaxBytes
+}
+
+// Limit is the largest upload of any allowed type, for bounding a request
+// before its type is known.
+func (p Policy) Limit() int64 {
+	limit := int64(0)
+	for _, t := range p.AllowedTypes {
+		if max := p.MaxBytesFor(t); max > limit {
+			limit = max
+		}
+	}
+	return limit
+}
+
+// Check enforces the type and size limits on a file.
+func (p Policy) Check(filename string, size int64) error {
+	mimeType, ok := TypeOf(filename)
+	if !ok || !p.Allows(mimeType) {
+		return ErrTypeNotAllowed
+	}
+	if max := p.MaxBytesFor(mimeType); size > max {
+		return &TooLargeError{Limit: max}
+	}
+	return nil
+}
+
+// AllowedExtensions lists the extensions of the allowed types, for messages.
+func (p Policy) AllowedExtensions() []string {
+	var exts []string
+	for _, ext := range []string{".pdf", ".docx", ".txt"} {
+		if p.Allows(extensions[ext]) {
+			exts = append(exts, ext)
+		}
+	}
+	return exts
+}
+
+func supported(mimeType string) bool {
+	for _, t := range extensions {
+		if t == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// PolicySource looks up a tenant's policy override. A nil policy means the
+// tenant uses the default.
+type PolicySource interface {
+	FindUploadPolicy(tenantID string) (*Policy, error)
+}
+
+// Policies resolves the upload policy of each tenant.
+type Policies struct {
+	source   PolicySource
+	defaults Policy
+}
+
+// NewPolicies resolves tenant overrides from source, which may be nil to
+// apply defaults to everyone.
+func NewPolicies(source PolicySource, defaults Policy) *Policies {
+	return &Policies{source: source, defaults: defaults}
+}
+
+// For returns the tenant's policy, or the default if it has none or it can't
+// be loaded.
+func (p *Policies) For(tenantID string) Policy {
+	if p == nil {
+		return Default()
+	}
+	if p.source == nil {
+		return p.defaults
+	}
+
+	override, err := p.source.FindUploadPolicy(tenantID)
+	if err != nil {
+		logger.Warn("Failed to load upload policy, using default", logger.Merge(logger.Fields{"tenant_id": tenantID}, logger.WithError(err)))
+		return p.defaults
+	}
+	if override != nil {
+		return *override
+	}
+	return p.defaults
+}
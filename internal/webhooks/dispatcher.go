@@ -0,0 +1,310 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+const (
+	SignatureHeader = "X-DocAI-Signature"
+	EventHeader     = "X-DocAI-Event"
+	DeliveryHeader  = "X-DocAI-Delivery"
+
+	claimBatchSize = 20
+	claimLease     = time.Minute
+)
+
+type Config struct {
+	// MaxAttempts is how many times a delivery is tried before it is marked failed.
+	MaxAttempts int
+	// BaseBackoff is the delay after the first failed attempt; it doubles on
+	// each further failure up to MaxBackoff.
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// AllowPrivateNetworks lets webhooks reach loopback, link-local and
+	// private addresses. Only for tests and local development.
+	AllowPrivateNetworks bool
+}
+
+// Dispatcher turns document events into webhook deliveries and sends them.
+// Deliveries are persisted before they are sent, so pending retries survive
+// a restart.
+type Dispatcher struct {
+	repo   Repository
+	bus    *events.Bus
+	client *http.Client
+	cfg    Config
+	wake   chan struct{}
+}
+
+func NewDispatcher(repo Repository, bus *events.Bus, cfg Config) *Dispatcher {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 5 * time.Second
+	}
+	if cfg.MaxBackoff < cfg.BaseBackoff {
+		cfg.MaxBackoff = cfg.BaseBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+
+	return &Dispatcher{
+		repo:   repo,
+		bus:    bus,
+		client: newHTTPClient(cfg.Timeout, cfg.AllowPrivateNetworks),
+		cfg:    cfg,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Run consumes events and delivers webhooks until ctx is cancelled. The
+// subscription is lossless, so a burst of events slows publishers down
+// rather than losing deliveries; events already received when ctx is
+// cancelled are still recorded.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ch, unsubscribe := d.bus.SubscribeLossless(func(e events.Event) bool {
+		return supportedEvents[e.Type]
+	}, 256)
+	defer unsubscribe()
+
+	go d.deliverLoop(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			unsubscribe()
+			for e := range ch {
+				d.record(context.WithoutCancel(ctx), e)
+			}
+			return
+		case e, ok := <-ch:
+			if !ok {
+				return
+			}
+			d.record(ctx, e)
+		}
+	}
+}
+
+func (d *Dispatcher) record(ctx context.Context, e events.Event) {
+	if err := d.enqueue(ctx, e); err != nil {
+		logger.Error("Failed to enqueue webhook deliveries", logger.Merge(logger.Fields{"type": e.Type, logger.DocumentIDKey: e.DocumentID}, logger.WithError(err)))
+		return
+	}
+	d.Wake()
+}
+
+// Wake asks the dispatcher to look for due deliveries without waiting for
+// the next poll.
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// enqueue records a delivery for every subscription of the event's tenant
+// that listens to its type.
+func (d *Dispatcher) enqueue(ctx context.Context, e events.Event) error {
+	if e.TenantID == "" {
+		return tenant.ErrNoScope
+	}
+	ctx = tenant.WithScope(ctx, tenant.Scope{TenantID: e.TenantID})
+
+	subs, err := d.repo.FindActiveSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []Delivery
+	for _, sub := range subs {
+		if !sub.Events.Contains(e.Type) {
+			continue
+		}
+
+		deliveryID := uuid.New()
+		payload, err := json.Marshal(Payload{ID: deliveryID, Type: e.Type, CreatedAt: e.Timestamp, Data: e})
+		if err != nil {
+			return err
+		}
+
+		docID := e.DocumentID
+		deliveries = append(deliveries, Delivery{
+			ID:             deliveryID,
+			TenantID:       sub.TenantID,
+			SubscriptionID: sub.ID,
+			EventType:      string(e.Type),
+			DocumentID:     &docID,
+			Payload:        payload,
+			Status:         DeliveryPending,
+			NextAttemptAt:  &now,
+		})
+	}
+
+	return d.repo.CreateDeliveries(ctx, deliveries)
+}
+
+func (d *Dispatcher) deliverLoop(ctx context.Context) {
+	ctx = tenant.WithScope(ctx, tenant.System)
+
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		d.deliverDue(ctx)
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	for {
+		due, err := d.repo.ClaimDueDeliveries(ctx, time.Now(), claimLease, claimBatchSize)
+		if err != nil {
+			logger.Error("Failed to claim webhook deliveries", logger.WithError(err))
+			return
+		}
+		if len(due) == 0 {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range due {
+			wg.Add(1)
+			go func(delivery *Delivery) {
+				defer wg.Done()
+				d.deliver(ctx, delivery)
+			}(&due[i])
+		}
+		wg.Wait()
+
+		if len(due) < claimBatchSize || ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *Delivery) {
+	fields := logger.Fields{"delivery_id": delivery.ID, "subscription_id": delivery.SubscriptionID}
+
+	sub, err := d.repo.FindSubscriptionByID(ctx, delivery.SubscriptionID)
+	if err != nil || !sub.Active {
+		delivery.Status = DeliveryFailed
+		delivery.LastError = "subscription is no longer active"
+		delivery.NextAttemptAt = nil
+		d.save(ctx, delivery, fields)
+		return
+	}
+
+	delivery.Attempts++
+	status, err := d.send(ctx, sub, delivery)
+	delivery.ResponseStatus = status
+
+	if err == nil {
+		now := time.Now()
+		delivery.Status = DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		logger.Info("Webhook delivered", logger.Merge(fields, logger.Fields{"attempts": delivery.Attempts}))
+		d.save(ctx, delivery, fields)
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.cfg.MaxAttempts {
+		delivery.Status = DeliveryFailed
+		delivery.NextAttemptAt = nil
+		logger.Warn("Webhook delivery failed permanently", logger.Merge(fields, logger.WithError(err)))
+	} else {
+		next := time.Now().Add(d.backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+		logger.Warn("Webhook delivery failed, will retry", logger.Merge(fields, logger.Fields{"attempts": delivery.Attempts, "next_attempt_at": next}, logger.WithError(err)))
+	}
+	d.save(ctx, delivery, fields)
+}
+
+func (d *Dispatcher) send(ctx context.Context, sub *Subscription, delivery *Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "DocAI-Webhooks/1.0")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(SignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, Sign(sub.Secret, timestamp, delivery.Payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// the body is not kept: tenants can read delivery errors, and it may come
+	// from anywhere the endpoint redirected to
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) save(ctx context.Context, delivery *Delivery, fields logger.Fields) {
+	if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+		logger.Error("Failed to record webhook delivery", logger.Merge(fields, logger.WithError(err)))
+	}
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.cfg.MaxBackoff {
+			return d.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
+
+// Sign computes the hex HMAC-SHA256 of "<timestamp>.<body>" with the
+// subscription secret. Receivers should recompute it from the t= value of
+// the signature header and compare in constant time.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,163 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/pkg/id"
+)
+
+const deliveryLogLimit = 100
+
+type Handler struct {
+	service *Service
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	var input SubscriptionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	sub, secret, err := h.service.CreateSubscription(r.Context(), input)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"subscription": sub,
+		"secret":       secret,
+	})
+}
+
+func (h *Handler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.service.ListSubscriptions(r.Context())
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subs)
+}
+
+func (h *Handler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	subID, ok := parseID(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	sub, err := h.service.GetSubscription(r.Context(), subID)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	subID, ok := parseID(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	var input SubscriptionInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid request body"))
+		return
+	}
+
+	sub, err := h.service.UpdateSubscription(r.Context(), subID, input)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	subID, ok := parseID(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSubscription(r.Context(), subID); err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	subID, ok := parseID(w, r, mux.Vars(r)["id"])
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(r.Context(), subID, deliveryLogLimit)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+func (h *Handler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	subID, ok := parseID(w, r, vars["id"])
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseID(w, r, vars["deliveryID"])
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.ReplayDelivery(r.Context(), subID, deliveryID)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, delivery)
+}
+
+func (h *Handler) writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidURL), errors.Is(err, ErrInvalidEvents):
+		apierror.Write(w, r, apierror.BadRequest(err.Error()))
+	case h.service.IsNotFoundError(err):
+		apierror.Write(w, r, apierror.NotFound("Webhook not found"))
+	default:
+		apierror.Write(w, r, err)
+	}
+}
+
+func parseID(w http.ResponseWriter, r *http.Request, raw string) (uuid.UUID, bool) {
+	parsed, err := id.IsValidUUID(raw)
+	if err != nil {
+		apierror.Write(w, r, apierror.BadRequest("Invalid ID format"))
+		return parsed, false
+	}
+	return parsed, true
+}
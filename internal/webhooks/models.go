@@ -0,0 +1,130 @@
+package webhooks
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/events"
+	"gorm.io/gorm"
+)
+
+// Event types that can be subscribed to.
+var supportedEvents = map[events.Type]bool{
+	events.DocumentUploaded: true,
+	events.StatusChanged:    true,
+	events.DocumentAnalyzed: true,
+	events.DocumentFailed:   true,
+	events.DocumentRejected: true,
+}
+
+// supportedEventNames lists supportedEvents in a stable order, for messages.
+func supportedEventNames() []string {
+	names := make([]string, 0, len(supportedEvents))
+	for t := range supportedEvents {
+		names = append(names, string(t))
+	}
+	sort.Strings(names)
+	return names
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// EventList is stored as a JSONB array of event types.
+type EventList []string
+
+func (l EventList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+func (l *EventList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("unsupported type for EventList: %T", value)
+	}
+}
+
+func (l EventList) Contains(t events.Type) bool {
+	for _, e := range l {
+		if e == string(t) {
+			return true
+		}
+	}
+	return false
+}
+
+type Subscription struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	TenantID    string    `json:"tenant_id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"-"`
+	Events      EventList `gorm:"type:jsonb" json:"events"`
+	Description string    `json:"description"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+func (s *Subscription) BeforeCreate(tx *gorm.DB) (err error) {
+	s.ID = uuid.New()
+	return
+}
+
+type Delivery struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primary_key;" json:"id"`
+	TenantID       string          `json:"tenant_id"`
+	SubscriptionID uuid.UUID       `gorm:"type:uuid" json:"subscription_id"`
+	EventType      string          `json:"event_type"`
+	DocumentID     *uuid.UUID      `gorm:"type:uuid" json:"document_id,omitempty"`
+	Payload        json.RawMessage `gorm:"type:jsonb" json:"payload"`
+	Status         string          `json:"status"` // pending, succeeded, failed
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	ReplayOf       *uuid.UUID      `gorm:"type:uuid" json:"replay_of,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (d *Delivery) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return
+}
+
+// Payload is the JSON body POSTed to subscribers.
+type Payload struct {
+	ID        uuid.UUID    `json:"id"`
+	Type      events.Type  `json:"type"`
+	CreatedAt time.Time    `json:"created_at"`
+	Data      events.Event `json:"data"`
+}
@@ -0,0 +1,169 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// Repository methods are scoped to the tenant carried by ctx. The dispatcher
+// runs with tenant.System so it can work through every tenant's deliveries.
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	FindSubscriptionByID(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	FindActiveSubscriptions(ctx context.Context) ([]Subscription, error)
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	CreateDeliveries(ctx context.Context, deliveries []Delivery) error
+	FindDelivery(ctx context.Context, subscriptionID, id uuid.UUID) (*Delivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]Delivery, error)
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error)
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+
+	IsNotFoundError(err error) bool
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		if !scope.Allows(sub.TenantID, "") {
+			return tenant.ErrOutOfScope
+		}
+		return tx.Create(sub).Error
+	})
+}
+
+func (r *repository) FindSubscriptionByID(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	var sub Subscription
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx).First(&sub, "id = ?", id).Error
+	})
+	return &sub, err
+}
+
+func (r *repository) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	var subs []Subscription
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx).Order("created_at").Find(&subs).Error
+	})
+	return subs, err
+}
+
+func (r *repository) FindActiveSubscriptions(ctx context.Context) ([]Subscription, error) {
+	var subs []Subscription
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx).Where("active = ?", true).Find(&subs).Error
+	})
+	return subs, err
+}
+
+func (r *repository) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		result := scope.FilterTenant(tx.Model(sub)).
+			Select("*").
+			Omit("id", "tenant_id", "created_at").
+			Updates(sub)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *repository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		result := scope.FilterTenant(tx).Delete(&Subscription{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *repository) CreateDeliveries(ctx context.Context, deliveries []Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		for _, d := range deliveries {
+			if !scope.Allows(d.TenantID, "") {
+				return tenant.ErrOutOfScope
+			}
+		}
+		return tx.Create(&deliveries).Error
+	})
+}
+
+func (r *repository) FindDelivery(ctx context.Context, subscriptionID, id uuid.UUID) (*Delivery, error) {
+	var delivery Delivery
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx).First(&delivery, "id = ? AND subscription_id = ?", id, subscriptionID).Error
+	})
+	return &delivery, err
+}
+
+func (r *repository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return scope.FilterTenant(tx).
+			Where("subscription_id = ?", subscriptionID).
+			Order("created_at DESC").
+			Limit(limit).
+			Find(&deliveries).Error
+	})
+	return deliveries, err
+}
+
+// ClaimDueDeliveries pushes the next attempt of due deliveries forward by the
+// lease and returns them, so that concurrent dispatchers never pick up the
+// same delivery twice.
+func (r *repository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		return tx.Raw(`
+			UPDATE webhook_deliveries SET next_attempt_at = ?, updated_at = ?
+			WHERE id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = ? AND next_attempt_at <= ?
+				ORDER BY next_attempt_at
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *`,
+			now.Add(lease), now, DeliveryPending, now, limit,
+		).Scan(&deliveries).Error
+	})
+	return deliveries, err
+}
+
+func (r *repository) UpdateDelivery(ctx context.Context, delivery *Delivery) error {
+	return tenant.Transaction(ctx, r.db, func(tx *gorm.DB, scope tenant.Scope) error {
+		if !scope.Allows(delivery.TenantID, "") {
+			return tenant.ErrOutOfScope
+		}
+		return tx.Save(delivery).Error
+	})
+}
+
+func (r *repository) IsNotFoundError(err error) bool {
+	return errors.Is(err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,16 @@
+package webhooks
+
+import (
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/auth"
+)
+
+func RegisterRoutes(r *mux.Router, h *Handler) {
+	r.HandleFunc("/webhooks", auth.RequireScope(auth.ScopeAdmin, h.CreateSubscription)).Methods("POST")
+	r.HandleFunc("/webhooks", auth.RequireScope(auth.ScopeAdmin, h.ListSubscriptions)).Methods("GET")
+	r.HandleFunc("/webhooks/{id}", auth.RequireScope(auth.ScopeAdmin, h.GetSubscription)).Methods("GET")
+	r.HandleFunc("/webhooks/{id}", auth.RequireScope(auth.ScopeAdmin, h.UpdateSubscription)).Methods("PUT")
+	r.HandleFunc("/webhooks/{id}", auth.RequireScope(auth.ScopeAdmin, h.DeleteSubscription)).Methods("DELETE")
+	r.HandleFunc("/webhooks/{id}/deliveries", auth.RequireScope(auth.ScopeAdmin, h.ListDeliveries)).Methods("GET")
+	r.HandleFunc("/webhooks/{id}/deliveries/{deliveryID}/replay", auth.RequireScope(auth.ScopeAdmin, h.ReplayDelivery)).Methods("POST")
+}
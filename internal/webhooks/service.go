@@ -0,0 +1,183 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+var (
+	ErrInvalidURL    = errors.New("url must be an absolute http or https URL")
+	ErrInvalidEvents = errors.New("events must list at least one of: " + strings.Join(supportedEventNames(), ", "))
+)
+
+type SubscriptionInput struct {
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Description string   `json:"description"`
+	Secret      string   `json:"secret,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+type Service struct {
+	repo       Repository
+	dispatcher *Dispatcher
+}
+
+func NewService(repo Repository, dispatcher *Dispatcher) *Service {
+	return &Service{
+		repo:       repo,
+		dispatcher: dispatcher,
+	}
+}
+
+// CreateSubscription stores a new subscription. If no secret is supplied one
+// is generated; it is returned here and never again.
+func (s *Service) CreateSubscription(ctx context.Context, input SubscriptionInput) (*Subscription, string, error) {
+	if err := validateInput(input); err != nil {
+		return nil, "", err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateSecret(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	scope, ok := tenant.FromContext(ctx)
+	if !ok || scope.IsSystem() {
+		return nil, "", tenant.ErrNoScope
+	}
+
+	sub := &Subscription{
+		TenantID:    scope.TenantID,
+		URL:         input.URL,
+		Secret:      secret,
+		Events:      EventList(input.Events),
+		Description: input.Description,
+		Active:      input.Active == nil || *input.Active,
+	}
+
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		logger.Error("Failed to create webhook subscription", logger.WithError(err))
+		return nil, "", err
+	}
+
+	logger.Info("Webhook subscription created", logger.Fields{"subscription_id": sub.ID, "url": sub.URL})
+	return sub, secret, nil
+}
+
+func (s *Service) GetSubscription(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	return s.repo.FindSubscriptionByID(ctx, id)
+}
+
+func (s *Service) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+func (s *Service) UpdateSubscription(ctx context.Context, id uuid.UUID, input SubscriptionInput) (*Subscription, error) {
+	if err := validateInput(input); err != nil {
+		return nil, err
+	}
+
+	sub, err := s.repo.FindSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.URL = input.URL
+	sub.Events = EventList(input.Events)
+	sub.Description = input.Description
+	if input.Active != nil {
+		sub.Active = *input.Active
+	}
+	if input.Secret != "" {
+		sub.Secret = input.Secret
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *Service) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func (s *Service) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]Delivery, error) {
+	if _, err := s.repo.FindSubscriptionByID(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, subscriptionID, limit)
+}
+
+// ReplayDelivery queues a fresh delivery of a previously sent payload. The
+// original delivery is left untouched in the log.
+func (s *Service) ReplayDelivery(ctx context.Context, subscriptionID, deliveryID uuid.UUID) (*Delivery, error) {
+	original, err := s.repo.FindDelivery(ctx, subscriptionID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	replay := Delivery{
+		ID:             uuid.New(),
+		TenantID:       original.TenantID,
+		SubscriptionID: original.SubscriptionID,
+		EventType:      original.EventType,
+		DocumentID:     original.DocumentID,
+		Payload:        original.Payload,
+		Status:         DeliveryPending,
+		ReplayOf:       &original.ID,
+		NextAttemptAt:  &now,
+	}
+
+	if err := s.repo.CreateDeliveries(ctx, []Delivery{replay}); err != nil {
+		return nil, err
+	}
+	s.dispatcher.Wake()
+
+	return &replay, nil
+}
+
+func (s *Service) IsNotFoundError(err error) bool {
+	return s.repo.IsNotFoundError(err)
+}
+
+func validateInput(input SubscriptionInput) error {
+	u, err := url.Parse(input.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+
+	if len(input.Events) == 0 {
+		return ErrInvalidEvents
+	}
+	for _, e := range input.Events {
+		if !supportedEvents[events.Type(e)] {
+			return fmt.Errorf("%w (got %q)", ErrInvalidEvents, e)
+		}
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_documents_status;
+DROP TABLE IF EXISTS analysis_batch_items;
+DROP TABLE IF EXISTS analysis_batches;
@@ -0,0 +1,4 @@
+ALTER TABLE documents DROP CONSTRAINT IF EXISTS documents_status_check;
+ALTER TABLE documents DROP COLUMN version;
+ALTER TABLE documents DROP COLUMN attempts;
+ALTER TABLE documents DROP COLUMN failure_reason;
@@ -0,0 +1,8 @@
+UPDATE documents SET status = 'analyzed' WHERE status = 'processed';
+UPDATE documents SET status = 'failed' WHERE status NOT IN ('uploaded', 'queued', 'processing', 'analyzed', 'failed');
+
+ALTER TABLE documents ADD COLUMN failure_reason TEXT;
+ALTER TABLE documents ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE documents ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE documents ADD CONSTRAINT documents_status_check
+    CHECK (status IN ('uploaded', 'queued', 'processing', 'analyzed', 'failed'));
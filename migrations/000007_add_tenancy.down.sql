@@ -0,0 +1,26 @@
+DROP POLICY IF EXISTS tenant_isolation ON webhook_deliveries;
+ALTER TABLE webhook_deliveries NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON webhook_subscriptions;
+ALTER TABLE webhook_subscriptions NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON analysis_batches;
+ALTER TABLE analysis_batches NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE analysis_batches DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON documents;
+ALTER TABLE documents NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE documents DISABLE ROW LEVEL SECURITY;
+
+ALTER TABLE api_keys DROP COLUMN tenant_id;
+ALTER TABLE webhook_deliveries DROP COLUMN tenant_id;
+ALTER TABLE webhook_subscriptions DROP COLUMN tenant_id;
+ALTER TABLE analysis_batches DROP COLUMN owner_id;
+ALTER TABLE analysis_batches DROP COLUMN tenant_id;
+DROP INDEX IF EXISTS idx_documents_tenant_filename;
+ALTER TABLE documents DROP COLUMN owner_id;
+ALTER TABLE documents DROP COLUMN tenant_id;
+
+DROP TABLE IF EXISTS tenants;
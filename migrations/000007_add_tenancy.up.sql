@@ -0,0 +1,54 @@
+CREATE TABLE IF NOT EXISTS tenants (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO tenants (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE documents ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE documents ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE documents ADD COLUMN owner_id TEXT;
+CREATE INDEX IF NOT EXISTS idx_documents_tenant_filename ON documents (tenant_id, filename);
+
+ALTER TABLE analysis_batches ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE analysis_batches ALTER COLUMN tenant_id DROP DEFAULT;
+ALTER TABLE analysis_batches ADD COLUMN owner_id TEXT;
+
+ALTER TABLE webhook_subscriptions ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE webhook_subscriptions ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE webhook_deliveries ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE webhook_deliveries ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE api_keys ADD COLUMN tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE api_keys ALTER COLUMN tenant_id DROP DEFAULT;
+
+-- Row-level security is a backstop for the tenant filters in the repositories.
+-- The application sets app.tenant_id per transaction; '*' is reserved for
+-- background jobs that work across tenants. Superusers bypass these policies,
+-- so the server must connect as an ordinary role.
+ALTER TABLE documents ENABLE ROW LEVEL SECURITY;
+ALTER TABLE documents FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON documents
+    USING (current_setting('app.tenant_id', true) IN ('*', tenant_id))
+    WITH CHECK (current_setting('app.tenant_id', true) IN ('*', tenant_id));
+
+ALTER TABLE analysis_batches ENABLE ROW LEVEL SECURITY;
+ALTER TABLE analysis_batches FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON analysis_batches
+    USING (current_setting('app.tenant_id', true) IN ('*', tenant_id))
+    WITH CHECK (current_setting('app.tenant_id', true) IN ('*', tenant_id));
+
+ALTER TABLE webhook_subscriptions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_subscriptions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_subscriptions
+    USING (current_setting('app.tenant_id', true) IN ('*', tenant_id))
+    WITH CHECK (current_setting('app.tenant_id', true) IN ('*', tenant_id));
+
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON webhook_deliveries
+    USING (current_setting('app.tenant_id', true) IN ('*', tenant_id))
+    WITH CHECK (current_setting('app.tenant_id', true) IN ('*', tenant_id));
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS tenant_usage;
+
+ALTER TABLE documents DROP COLUMN size_bytes;
+
+ALTER TABLE tenants DROP COLUMN limit_monthly_tokens;
+ALTER TABLE tenants DROP COLUMN limit_storage_bytes;
+ALTER TABLE tenants DROP COLUMN limit_daily_analyses;
+ALTER TABLE tenants DROP COLUMN limit_requests_per_minute;
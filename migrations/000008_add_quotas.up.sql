@@ -0,0 +1,17 @@
+-- Per-tenant overrides of the configured quota defaults. NULL means "use the
+-- default"; 0 means unlimited.
+ALTER TABLE tenants ADD COLUMN limit_requests_per_minute INTEGER;
+ALTER TABLE tenants ADD COLUMN limit_daily_analyses BIGINT;
+ALTER TABLE tenants ADD COLUMN limit_storage_bytes BIGINT;
+ALTER TABLE tenants ADD COLUMN limit_monthly_tokens BIGINT;
+
+ALTER TABLE documents ADD COLUMN size_bytes BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS tenant_usage (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    metric TEXT NOT NULL,
+    period DATE NOT NULL,
+    amount BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, metric, period)
+);
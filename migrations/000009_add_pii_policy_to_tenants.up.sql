@@ -0,0 +1,2 @@
+-- NULL means the tenant uses the server's default PII policy.
+ALTER TABLE tenants ADD COLUMN pii_policy JSONB;
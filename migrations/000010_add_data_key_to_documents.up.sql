@@ -0,0 +1,3 @@
+-- Wrapped per-document data key. Empty for documents stored before
+-- encryption at rest was enabled; those stay readable as plaintext.
+ALTER TABLE documents ADD COLUMN data_key TEXT NOT NULL DEFAULT '';
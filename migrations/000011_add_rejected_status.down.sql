@@ -0,0 +1,6 @@
+-- documents are under FORCE ROW LEVEL SECURITY; see every tenant's rows
+SELECT set_config('app.tenant_id', '*', true);
+UPDATE documents SET status = 'failed' WHERE status = 'rejected';
+ALTER TABLE documents DROP CONSTRAINT IF EXISTS documents_status_check;
+ALTER TABLE documents ADD CONSTRAINT documents_status_check
+    CHECK (status IN ('uploaded', 'queued', 'processing', 'analyzed', 'failed'));
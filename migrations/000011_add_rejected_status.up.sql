@@ -0,0 +1,3 @@
+ALTER TABLE documents DROP CONSTRAINT IF EXISTS documents_status_check;
+ALTER TABLE documents ADD CONSTRAINT documents_status_check
+    CHECK (status IN ('uploaded', 'queued', 'processing', 'analyzed', 'failed', 'rejected'));
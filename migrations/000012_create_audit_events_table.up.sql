@@ -0,0 +1,34 @@
+-- Append-only record of who did what to which document. document_id has no
+-- foreign key so the trail outlives the documents it describes.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id),
+    actor TEXT NOT NULL,
+    actor_type TEXT NOT NULL,
+    action TEXT NOT NULL,
+    document_id UUID,
+    ip TEXT,
+    request_id TEXT,
+    outcome TEXT NOT NULL CHECK (outcome IN ('success', 'denied', 'failure')),
+    detail TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_tenant_created ON audit_events (tenant_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_events_document ON audit_events (document_id);
+
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+ALTER TABLE audit_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_events FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_events
+    USING (current_setting('app.tenant_id', true) IN ('*', tenant_id))
+    WITH CHECK (current_setting('app.tenant_id', true) IN ('*', tenant_id));
@@ -0,0 +1,2 @@
+-- NULL means the tenant uses the server's default upload policy.
+ALTER TABLE tenants ADD COLUMN upload_policy JSONB;
@@ -0,0 +1,4 @@
+-- locked_at is refreshed while the first request with a key is running; a
+-- key whose lock has not been refreshed for the lock timeout belongs to a
+-- server that died and can be taken over.
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
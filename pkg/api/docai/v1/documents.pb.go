@@ -0,0 +1,1616 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: docai/v1/documents.proto
+
+package docaiv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DocumentStatus int32
+
+const (
+	DocumentStatus_DOCUMENT_STATUS_UNSPECIFIED DocumentStatus = 0
+	DocumentStatus_DOCUMENT_STATUS_UPLOADED    DocumentStatus = 1
+	DocumentStatus_DOCUMENT_STATUS_QUEUED      DocumentStatus = 2
+	DocumentStatus_DOCUMENT_STATUS_PROCESSING  DocumentStatus = 3
+	DocumentStatus_DOCUMENT_STATUS_ANALYZED    DocumentStatus = 4
+	DocumentStatus_DOCUMENT_STATUS_FAILED      DocumentStatus = 5
+	DocumentStatus_DOCUMENT_STATUS_REJECTED    DocumentStatus = 6
+)
+
+// Enum value maps for DocumentStatus.
+var (
+	DocumentStatus_name = map[int32]string{
+		0: "DOCUMENT_STATUS_UNSPECIFIED",
+		1: "DOCUMENT_STATUS_UPLOADED",
+		2: "DOCUMENT_STATUS_QUEUED",
+		3: "DOCUMENT_STATUS_PROCESSING",
+		4: "DOCUMENT_STATUS_ANALYZED",
+		5: "DOCUMENT_STATUS_FAILED",
+		6: "DOCUMENT_STATUS_REJECTED",
+	}
+	DocumentStatus_value = map[string]int32{
+		"DOCUMENT_STATUS_UNSPECIFIED": 0,
+		"DOCUMENT_STATUS_UPLOADED":    1,
+		"DOCUMENT_STATUS_QUEUED":      2,
+		"DOCUMENT_STATUS_PROCESSING":  3,
+		"DOCUMENT_STATUS_ANALYZED":    4,
+		"DOCUMENT_STATUS_FAILED":      5,
+		"DOCUMENT_STATUS_REJECTED":    6,
+	}
+)
+
+func (x DocumentStatus) Enum() *DocumentStatus {
+	p := new(DocumentStatus)
+	*p = x
+	return p
+}
+
+func (x DocumentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DocumentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_docai_v1_documents_proto_enumTypes[0].Descriptor()
+}
+
+func (DocumentStatus) Type() protoreflect.EnumType {
+	return &file_docai_v1_documents_proto_enumTypes[0]
+}
+
+func (x DocumentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DocumentStatus.Descriptor instead.
+func (DocumentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{0}
+}
+
+type Document struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	OwnerId       string                 `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
+	FileUrl       string                 `protobuf:"bytes,5,opt,name=file_url,json=fileUrl,proto3" json:"file_url,omitempty"`
+	ContentType   string                 `protobuf:"bytes,6,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	SizeBytes     int64                  `protobuf:"varint,7,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	ExtractedText string                 `protobuf:"bytes,8,opt,name=extracted_text,json=extractedText,proto3" json:"extracted_text,omitempty"`
+	Summary       string                 `protobuf:"bytes,9,opt,name=summary,proto3" json:"summary,omitempty"`
+	DocType       string                 `protobuf:"bytes,10,opt,name=doc_type,json=docType,proto3" json:"doc_type,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,11,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Status        DocumentStatus         `protobuf:"varint,12,opt,name=status,proto3,enum=docai.v1.DocumentStatus" json:"status,omitempty"`
+	FailureReason string                 `protobuf:"bytes,13,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	Attempts      int32                  `protobuf:"varint,14,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	Version       int32                  `protobuf:"varint,15,opt,name=version,proto3" json:"version,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Document) Reset() {
+	*x = Document{}
+	mi := &file_docai_v1_documents_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Document) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Document) ProtoMessage() {}
+
+func (x *Document) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Document.ProtoReflect.Descriptor instead.
+func (*Document) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Document) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Document) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Document) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *Document) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *Document) GetFileUrl() string {
+	if x != nil {
+		return x.FileUrl
+	}
+	return ""
+}
+
+func (x *Document) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Document) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Document) GetExtractedText() string {
+	if x != nil {
+		return x.ExtractedText
+	}
+	return ""
+}
+
+func (x *Document) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Document) GetDocType() string {
+	if x != nil {
+		return x.DocType
+	}
+	return ""
+}
+
+func (x *Document) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Document) GetStatus() DocumentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DocumentStatus_DOCUMENT_STATUS_UNSPECIFIED
+}
+
+func (x *Document) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *Document) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Document) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Document) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Document) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type UploadDocumentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadDocumentRequest_Metadata
+	//	*UploadDocumentRequest_Chunk
+	Data          isUploadDocumentRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadDocumentRequest) Reset() {
+	*x = UploadDocumentRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadDocumentRequest) ProtoMessage() {}
+
+func (x *UploadDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadDocumentRequest.ProtoReflect.Descriptor instead.
+func (*UploadDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *UploadDocumentRequest) GetData() isUploadDocumentRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadDocumentRequest) GetMetadata() *UploadMetadata {
+	if x != nil {
+		if x, ok := x.Data.(*UploadDocumentRequest_Metadata); ok {
+			return x.Metadata
+		}
+	}
+	return nil
+}
+
+func (x *UploadDocumentRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*UploadDocumentRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadDocumentRequest_Data interface {
+	isUploadDocumentRequest_Data()
+}
+
+type UploadDocumentRequest_Metadata struct {
+	Metadata *UploadMetadata `protobuf:"bytes,1,opt,name=metadata,proto3,oneof"`
+}
+
+type UploadDocumentRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadDocumentRequest_Metadata) isUploadDocumentRequest_Data() {}
+
+func (*UploadDocumentRequest_Chunk) isUploadDocumentRequest_Data() {}
+
+type UploadMetadata struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Filename    string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// size_bytes lets oversized uploads be refused before any content is sent.
+	SizeBytes int64 `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	// process_immediately queues the document for analysis after upload.
+	ProcessImmediately bool `protobuf:"varint,4,opt,name=process_immediately,json=processImmediately,proto3" json:"process_immediately,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UploadMetadata) Reset() {
+	*x = UploadMetadata{}
+	mi := &file_docai_v1_documents_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMetadata) ProtoMessage() {}
+
+func (x *UploadMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMetadata.ProtoReflect.Descriptor instead.
+func (*UploadMetadata) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UploadMetadata) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *UploadMetadata) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadMetadata) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *UploadMetadata) GetProcessImmediately() bool {
+	if x != nil {
+		return x.ProcessImmediately
+	}
+	return false
+}
+
+type UploadDocumentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Document      *Document              `protobuf:"bytes,2,opt,name=document,proto3" json:"document,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadDocumentResponse) Reset() {
+	*x = UploadDocumentResponse{}
+	mi := &file_docai_v1_documents_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadDocumentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadDocumentResponse) ProtoMessage() {}
+
+func (x *UploadDocumentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadDocumentResponse.ProtoReflect.Descriptor instead.
+func (*UploadDocumentResponse) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UploadDocumentResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *UploadDocumentResponse) GetDocument() *Document {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+type GetDocumentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDocumentRequest) Reset() {
+	*x = GetDocumentRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDocumentRequest) ProtoMessage() {}
+
+func (x *GetDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDocumentRequest.ProtoReflect.Descriptor instead.
+func (*GetDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetDocumentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListDocumentsRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Status  DocumentStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=docai.v1.DocumentStatus" json:"status,omitempty"`
+	DocType string                 `protobuf:"bytes,2,opt,name=doc_type,json=docType,proto3" json:"doc_type,omitempty"`
+	// limit defaults to 50 and may be at most 200.
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDocumentsRequest) Reset() {
+	*x = ListDocumentsRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDocumentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDocumentsRequest) ProtoMessage() {}
+
+func (x *ListDocumentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDocumentsRequest.ProtoReflect.Descriptor instead.
+func (*ListDocumentsRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListDocumentsRequest) GetStatus() DocumentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DocumentStatus_DOCUMENT_STATUS_UNSPECIFIED
+}
+
+func (x *ListDocumentsRequest) GetDocType() string {
+	if x != nil {
+		return x.DocType
+	}
+	return ""
+}
+
+func (x *ListDocumentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDocumentsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListDocumentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Documents     []*Document            `protobuf:"bytes,1,rep,name=documents,proto3" json:"documents,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDocumentsResponse) Reset() {
+	*x = ListDocumentsResponse{}
+	mi := &file_docai_v1_documents_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDocumentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDocumentsResponse) ProtoMessage() {}
+
+func (x *ListDocumentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDocumentsResponse.ProtoReflect.Descriptor instead.
+func (*ListDocumentsResponse) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListDocumentsResponse) GetDocuments() []*Document {
+	if x != nil {
+		return x.Documents
+	}
+	return nil
+}
+
+type DownloadDocumentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadDocumentRequest) Reset() {
+	*x = DownloadDocumentRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadDocumentRequest) ProtoMessage() {}
+
+func (x *DownloadDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadDocumentRequest.ProtoReflect.Descriptor instead.
+func (*DownloadDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DownloadDocumentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DownloadDocumentResponse carries the file name and type in the first
+// message only.
+type DownloadDocumentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Chunk         []byte                 `protobuf:"bytes,3,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadDocumentResponse) Reset() {
+	*x = DownloadDocumentResponse{}
+	mi := &file_docai_v1_documents_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadDocumentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadDocumentResponse) ProtoMessage() {}
+
+func (x *DownloadDocumentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadDocumentResponse.ProtoReflect.Descriptor instead.
+func (*DownloadDocumentResponse) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DownloadDocumentResponse) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *DownloadDocumentResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *DownloadDocumentResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type AnalyzeDocumentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeDocumentRequest) Reset() {
+	*x = AnalyzeDocumentRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeDocumentRequest) ProtoMessage() {}
+
+func (x *AnalyzeDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeDocumentRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AnalyzeDocumentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type AnalyzeBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	Filter        *BatchFilter           `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeBatchRequest) Reset() {
+	*x = AnalyzeBatchRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeBatchRequest) ProtoMessage() {}
+
+func (x *AnalyzeBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeBatchRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeBatchRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AnalyzeBatchRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *AnalyzeBatchRequest) GetFilter() *BatchFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+// BatchFilter selects documents for a batch. Documents that are already
+// queued or processing are never matched.
+type BatchFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        DocumentStatus         `protobuf:"varint,1,opt,name=status,proto3,enum=docai.v1.DocumentStatus" json:"status,omitempty"`
+	DocType       string                 `protobuf:"bytes,2,opt,name=doc_type,json=docType,proto3" json:"doc_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchFilter) Reset() {
+	*x = BatchFilter{}
+	mi := &file_docai_v1_documents_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchFilter) ProtoMessage() {}
+
+func (x *BatchFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchFilter.ProtoReflect.Descriptor instead.
+func (*BatchFilter) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchFilter) GetStatus() DocumentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DocumentStatus_DOCUMENT_STATUS_UNSPECIFIED
+}
+
+func (x *BatchFilter) GetDocType() string {
+	if x != nil {
+		return x.DocType
+	}
+	return ""
+}
+
+type AnalysisBatch struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	OwnerId  string                 `protobuf:"bytes,3,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	// status is running, completed or interrupted.
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Total         int32                  `protobuf:"varint,5,opt,name=total,proto3" json:"total,omitempty"`
+	Succeeded     int32                  `protobuf:"varint,6,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Failed        int32                  `protobuf:"varint,7,opt,name=failed,proto3" json:"failed,omitempty"`
+	Failures      []*AnalysisBatchItem   `protobuf:"bytes,8,rep,name=failures,proto3" json:"failures,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalysisBatch) Reset() {
+	*x = AnalysisBatch{}
+	mi := &file_docai_v1_documents_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisBatch) ProtoMessage() {}
+
+func (x *AnalysisBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisBatch.ProtoReflect.Descriptor instead.
+func (*AnalysisBatch) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AnalysisBatch) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AnalysisBatch) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *AnalysisBatch) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *AnalysisBatch) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AnalysisBatch) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *AnalysisBatch) GetSucceeded() int32 {
+	if x != nil {
+		return x.Succeeded
+	}
+	return 0
+}
+
+func (x *AnalysisBatch) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *AnalysisBatch) GetFailures() []*AnalysisBatchItem {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+func (x *AnalysisBatch) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AnalysisBatch) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *AnalysisBatch) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+type AnalysisBatchItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DocumentId    string                 `protobuf:"bytes,1,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalysisBatchItem) Reset() {
+	*x = AnalysisBatchItem{}
+	mi := &file_docai_v1_documents_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalysisBatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalysisBatchItem) ProtoMessage() {}
+
+func (x *AnalysisBatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalysisBatchItem.ProtoReflect.Descriptor instead.
+func (*AnalysisBatchItem) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AnalysisBatchItem) GetDocumentId() string {
+	if x != nil {
+		return x.DocumentId
+	}
+	return ""
+}
+
+func (x *AnalysisBatchItem) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AnalysisBatchItem) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AnalysisBatchItem) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type GetBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBatchRequest) Reset() {
+	*x = GetBatchRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBatchRequest) ProtoMessage() {}
+
+func (x *GetBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBatchRequest.ProtoReflect.Descriptor instead.
+func (*GetBatchRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetBatchRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WatchDocumentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDocumentRequest) Reset() {
+	*x = WatchDocumentRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDocumentRequest) ProtoMessage() {}
+
+func (x *WatchDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDocumentRequest.ProtoReflect.Descriptor instead.
+func (*WatchDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *WatchDocumentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WatchEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsRequest) Reset() {
+	*x = WatchEventsRequest{}
+	mi := &file_docai_v1_documents_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsRequest) ProtoMessage() {}
+
+func (x *WatchEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsRequest.ProtoReflect.Descriptor instead.
+func (*WatchEventsRequest) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{16}
+}
+
+type DocumentEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// type is the event type, e.g. document.status or document.progress.
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	DocumentId     string                 `protobuf:"bytes,3,opt,name=document_id,json=documentId,proto3" json:"document_id,omitempty"`
+	TenantId       string                 `protobuf:"bytes,4,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	OwnerId        string                 `protobuf:"bytes,5,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Status         DocumentStatus         `protobuf:"varint,6,opt,name=status,proto3,enum=docai.v1.DocumentStatus" json:"status,omitempty"`
+	PreviousStatus DocumentStatus         `protobuf:"varint,7,opt,name=previous_status,json=previousStatus,proto3,enum=docai.v1.DocumentStatus" json:"previous_status,omitempty"`
+	Progress       *Progress              `protobuf:"bytes,8,opt,name=progress,proto3" json:"progress,omitempty"`
+	Error          string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	Data           *structpb.Struct       `protobuf:"bytes,10,opt,name=data,proto3" json:"data,omitempty"`
+	Timestamp      *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DocumentEvent) Reset() {
+	*x = DocumentEvent{}
+	mi := &file_docai_v1_documents_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentEvent) ProtoMessage() {}
+
+func (x *DocumentEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentEvent.ProtoReflect.Descriptor instead.
+func (*DocumentEvent) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DocumentEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DocumentEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *DocumentEvent) GetDocumentId() string {
+	if x != nil {
+		return x.DocumentId
+	}
+	return ""
+}
+
+func (x *DocumentEvent) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DocumentEvent) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *DocumentEvent) GetStatus() DocumentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DocumentStatus_DOCUMENT_STATUS_UNSPECIFIED
+}
+
+func (x *DocumentEvent) GetPreviousStatus() DocumentStatus {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return DocumentStatus_DOCUMENT_STATUS_UNSPECIFIED
+}
+
+func (x *DocumentEvent) GetProgress() *Progress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *DocumentEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DocumentEvent) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *DocumentEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type Progress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Current       int32                  `protobuf:"varint,2,opt,name=current,proto3" json:"current,omitempty"`
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_docai_v1_documents_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_docai_v1_documents_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_docai_v1_documents_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Progress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Progress) GetCurrent() int32 {
+	if x != nil {
+		return x.Current
+	}
+	return 0
+}
+
+func (x *Progress) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+var File_docai_v1_documents_proto protoreflect.FileDescriptor
+
+const file_docai_v1_documents_proto_rawDesc = "" +
+	"\n" +
+	"\x18docai/v1/documents.proto\x12\bdocai.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe1\x04\n" +
+	"\bDocument\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x19\n" +
+	"\bowner_id\x18\x03 \x01(\tR\aownerId\x12\x1a\n" +
+	"\bfilename\x18\x04 \x01(\tR\bfilename\x12\x19\n" +
+	"\bfile_url\x18\x05 \x01(\tR\afileUrl\x12!\n" +
+	"\fcontent_type\x18\x06 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\a \x01(\x03R\tsizeBytes\x12%\n" +
+	"\x0eextracted_text\x18\b \x01(\tR\rextractedText\x12\x18\n" +
+	"\asummary\x18\t \x01(\tR\asummary\x12\x19\n" +
+	"\bdoc_type\x18\n" +
+	" \x01(\tR\adocType\x123\n" +
+	"\bmetadata\x18\v \x01(\v2\x17.google.protobuf.StructR\bmetadata\x120\n" +
+	"\x06status\x18\f \x01(\x0e2\x18.docai.v1.DocumentStatusR\x06status\x12%\n" +
+	"\x0efailure_reason\x18\r \x01(\tR\rfailureReason\x12\x1a\n" +
+	"\battempts\x18\x0e \x01(\x05R\battempts\x12\x18\n" +
+	"\aversion\x18\x0f \x01(\x05R\aversion\x129\n" +
+	"\n" +
+	"created_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"o\n" +
+	"\x15UploadDocumentRequest\x126\n" +
+	"\bmetadata\x18\x01 \x01(\v2\x18.docai.v1.UploadMetadataH\x00R\bmetadata\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"\x9f\x01\n" +
+	"\x0eUploadMetadata\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x03 \x01(\x03R\tsizeBytes\x12/\n" +
+	"\x13process_immediately\x18\x04 \x01(\bR\x12processImmediately\"b\n" +
+	"\x16UploadDocumentResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12.\n" +
+	"\bdocument\x18\x02 \x01(\v2\x12.docai.v1.DocumentR\bdocument\"$\n" +
+	"\x12GetDocumentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x91\x01\n" +
+	"\x14ListDocumentsRequest\x120\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.docai.v1.DocumentStatusR\x06status\x12\x19\n" +
+	"\bdoc_type\x18\x02 \x01(\tR\adocType\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"I\n" +
+	"\x15ListDocumentsResponse\x120\n" +
+	"\tdocuments\x18\x01 \x03(\v2\x12.docai.v1.DocumentR\tdocuments\")\n" +
+	"\x17DownloadDocumentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"o\n" +
+	"\x18DownloadDocumentResponse\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x14\n" +
+	"\x05chunk\x18\x03 \x01(\fR\x05chunk\"(\n" +
+	"\x16AnalyzeDocumentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"V\n" +
+	"\x13AnalyzeBatchRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12-\n" +
+	"\x06filter\x18\x02 \x01(\v2\x15.docai.v1.BatchFilterR\x06filter\"Z\n" +
+	"\vBatchFilter\x120\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.docai.v1.DocumentStatusR\x06status\x12\x19\n" +
+	"\bdoc_type\x18\x02 \x01(\tR\adocType\"\xa9\x03\n" +
+	"\rAnalysisBatch\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x19\n" +
+	"\bowner_id\x18\x03 \x01(\tR\aownerId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x14\n" +
+	"\x05total\x18\x05 \x01(\x05R\x05total\x12\x1c\n" +
+	"\tsucceeded\x18\x06 \x01(\x05R\tsucceeded\x12\x16\n" +
+	"\x06failed\x18\a \x01(\x05R\x06failed\x127\n" +
+	"\bfailures\x18\b \x03(\v2\x1b.docai.v1.AnalysisBatchItemR\bfailures\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12=\n" +
+	"\fcompleted_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\x9d\x01\n" +
+	"\x11AnalysisBatchItem\x12\x1f\n" +
+	"\vdocument_id\x18\x01 \x01(\tR\n" +
+	"documentId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"!\n" +
+	"\x0fGetBatchRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"&\n" +
+	"\x14WatchDocumentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
+	"\x12WatchEventsRequest\"\xae\x03\n" +
+	"\rDocumentEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1f\n" +
+	"\vdocument_id\x18\x03 \x01(\tR\n" +
+	"documentId\x12\x1b\n" +
+	"\ttenant_id\x18\x04 \x01(\tR\btenantId\x12\x19\n" +
+	"\bowner_id\x18\x05 \x01(\tR\aownerId\x120\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x18.docai.v1.DocumentStatusR\x06status\x12A\n" +
+	"\x0fprevious_status\x18\a \x01(\x0e2\x18.docai.v1.DocumentStatusR\x0epreviousStatus\x12.\n" +
+	"\bprogress\x18\b \x01(\v2\x12.docai.v1.ProgressR\bprogress\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12+\n" +
+	"\x04data\x18\n" +
+	" \x01(\v2\x17.google.protobuf.StructR\x04data\x128\n" +
+	"\ttimestamp\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"P\n" +
+	"\bProgress\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x18\n" +
+	"\acurrent\x18\x02 \x01(\x05R\acurrent\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total*\xe3\x01\n" +
+	"\x0eDocumentStatus\x12\x1f\n" +
+	"\x1bDOCUMENT_STATUS_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18DOCUMENT_STATUS_UPLOADED\x10\x01\x12\x1a\n" +
+	"\x16DOCUMENT_STATUS_QUEUED\x10\x02\x12\x1e\n" +
+	"\x1aDOCUMENT_STATUS_PROCESSING\x10\x03\x12\x1c\n" +
+	"\x18DOCUMENT_STATUS_ANALYZED\x10\x04\x12\x1a\n" +
+	"\x16DOCUMENT_STATUS_FAILED\x10\x05\x12\x1c\n" +
+	"\x18DOCUMENT_STATUS_REJECTED\x10\x062\xbd\x05\n" +
+	"\x0fDocumentService\x12U\n" +
+	"\x0eUploadDocument\x12\x1f.docai.v1.UploadDocumentRequest\x1a .docai.v1.UploadDocumentResponse(\x01\x12?\n" +
+	"\vGetDocument\x12\x1c.docai.v1.GetDocumentRequest\x1a\x12.docai.v1.Document\x12P\n" +
+	"\rListDocuments\x12\x1e.docai.v1.ListDocumentsRequest\x1a\x1f.docai.v1.ListDocumentsResponse\x12[\n" +
+	"\x10DownloadDocument\x12!.docai.v1.DownloadDocumentRequest\x1a\".docai.v1.DownloadDocumentResponse0\x01\x12G\n" +
+	"\x0fAnalyzeDocument\x12 .docai.v1.AnalyzeDocumentRequest\x1a\x12.docai.v1.Document\x12F\n" +
+	"\fAnalyzeBatch\x12\x1d.docai.v1.AnalyzeBatchRequest\x1a\x17.docai.v1.AnalysisBatch\x12>\n" +
+	"\bGetBatch\x12\x19.docai.v1.GetBatchRequest\x1a\x17.docai.v1.AnalysisBatch\x12J\n" +
+	"\rWatchDocument\x12\x1e.docai.v1.WatchDocumentRequest\x1a\x17.docai.v1.DocumentEvent0\x01\x12F\n" +
+	"\vWatchEvents\x12\x1c.docai.v1.WatchEventsRequest\x1a\x17.docai.v1.DocumentEvent0\x01B2Z0github.com/zjoart/docai/pkg/api/docai/v1;docaiv1b\x06proto3"
+
+var (
+	file_docai_v1_documents_proto_rawDescOnce sync.Once
+	file_docai_v1_documents_proto_rawDescData []byte
+)
+
+func file_docai_v1_documents_proto_rawDescGZIP() []byte {
+	file_docai_v1_documents_proto_rawDescOnce.Do(func() {
+		file_docai_v1_documents_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_docai_v1_documents_proto_rawDesc), len(file_docai_v1_documents_proto_rawDesc)))
+	})
+	return file_docai_v1_documents_proto_rawDescData
+}
+
+var file_docai_v1_documents_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_docai_v1_documents_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_docai_v1_documents_proto_goTypes = []any{
+	(DocumentStatus)(0),              // 0: docai.v1.DocumentStatus
+	(*Document)(nil),                 // 1: docai.v1.Document
+	(*UploadDocumentRequest)(nil),    // 2: docai.v1.UploadDocumentRequest
+	(*UploadMetadata)(nil),           // 3: docai.v1.UploadMetadata
+	(*UploadDocumentResponse)(nil),   // 4: docai.v1.UploadDocumentResponse
+	(*GetDocumentRequest)(nil),       // 5: docai.v1.GetDocumentRequest
+	(*ListDocumentsRequest)(nil),     // 6: docai.v1.ListDocumentsRequest
+	(*ListDocumentsResponse)(nil),    // 7: docai.v1.ListDocumentsResponse
+	(*DownloadDocumentRequest)(nil),  // 8: docai.v1.DownloadDocumentRequest
+	(*DownloadDocumentResponse)(nil), // 9: docai.v1.DownloadDocumentResponse
+	(*AnalyzeDocumentRequest)(nil),   // 10: docai.v1.AnalyzeDocumentRequest
+	(*AnalyzeBatchRequest)(nil),      // 11: docai.v1.AnalyzeBatchRequest
+	(*BatchFilter)(nil),              // 12: docai.v1.BatchFilter
+	(*AnalysisBatch)(nil),            // 13: docai.v1.AnalysisBatch
+	(*AnalysisBatchItem)(nil),        // 14: docai.v1.AnalysisBatchItem
+	(*GetBatchRequest)(nil),          // 15: docai.v1.GetBatchRequest
+	(*WatchDocumentRequest)(nil),     // 16: docai.v1.WatchDocumentRequest
+	(*WatchEventsRequest)(nil),       // 17: docai.v1.WatchEventsRequest
+	(*DocumentEvent)(nil),            // 18: docai.v1.DocumentEvent
+	(*Progress)(nil),                 // 19: docai.v1.Progress
+	(*structpb.Struct)(nil),          // 20: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),    // 21: google.protobuf.Timestamp
+}
+var file_docai_v1_documents_proto_depIdxs = []int32{
+	20, // 0: docai.v1.Document.metadata:type_name -> google.protobuf.Struct
+	0,  // 1: docai.v1.Document.status:type_name -> docai.v1.DocumentStatus
+	21, // 2: docai.v1.Document.created_at:type_name -> google.protobuf.Timestamp
+	21, // 3: docai.v1.Document.updated_at:type_name -> google.protobuf.Timestamp
+	3,  // 4: docai.v1.UploadDocumentRequest.metadata:type_name -> docai.v1.UploadMetadata
+	1,  // 5: docai.v1.UploadDocumentResponse.document:type_name -> docai.v1.Document
+	0,  // 6: docai.v1.ListDocumentsRequest.status:type_name -> docai.v1.DocumentStatus
+	1,  // 7: docai.v1.ListDocumentsResponse.documents:type_name -> docai.v1.Document
+	12, // 8: docai.v1.AnalyzeBatchRequest.filter:type_name -> docai.v1.BatchFilter
+	0,  // 9: docai.v1.BatchFilter.status:type_name -> docai.v1.DocumentStatus
+	14, // 10: docai.v1.AnalysisBatch.failures:type_name -> docai.v1.AnalysisBatchItem
+	21, // 11: docai.v1.AnalysisBatch.created_at:type_name -> google.protobuf.Timestamp
+	21, // 12: docai.v1.AnalysisBatch.updated_at:type_name -> google.protobuf.Timestamp
+	21, // 13: docai.v1.AnalysisBatch.completed_at:type_name -> google.protobuf.Timestamp
+	21, // 14: docai.v1.AnalysisBatchItem.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 15: docai.v1.DocumentEvent.status:type_name -> docai.v1.DocumentStatus
+	0,  // 16: docai.v1.DocumentEvent.previous_status:type_name -> docai.v1.DocumentStatus
+	19, // 17: docai.v1.DocumentEvent.progress:type_name -> docai.v1.Progress
+	20, // 18: docai.v1.DocumentEvent.data:type_name -> google.protobuf.Struct
+	21, // 19: docai.v1.DocumentEvent.timestamp:type_name -> google.protobuf.Timestamp
+	2,  // 20: docai.v1.DocumentService.UploadDocument:input_type -> docai.v1.UploadDocumentRequest
+	5,  // 21: docai.v1.DocumentService.GetDocument:input_type -> docai.v1.GetDocumentRequest
+	6,  // 22: docai.v1.DocumentService.ListDocuments:input_type -> docai.v1.ListDocumentsRequest
+	8,  // 23: docai.v1.DocumentService.DownloadDocument:input_type -> docai.v1.DownloadDocumentRequest
+	10, // 24: docai.v1.DocumentService.AnalyzeDocument:input_type -> docai.v1.AnalyzeDocumentRequest
+	11, // 25: docai.v1.DocumentService.AnalyzeBatch:input_type -> docai.v1.AnalyzeBatchRequest
+	15, // 26: docai.v1.DocumentService.GetBatch:input_type -> docai.v1.GetBatchRequest
+	16, // 27: docai.v1.DocumentService.WatchDocument:input_type -> docai.v1.WatchDocumentRequest
+	17, // 28: docai.v1.DocumentService.WatchEvents:input_type -> docai.v1.WatchEventsRequest
+	4,  // 29: docai.v1.DocumentService.UploadDocument:output_type -> docai.v1.UploadDocumentResponse
+	1,  // 30: docai.v1.DocumentService.GetDocument:output_type -> docai.v1.Document
+	7,  // 31: docai.v1.DocumentService.ListDocuments:output_type -> docai.v1.ListDocumentsResponse
+	9,  // 32: docai.v1.DocumentService.DownloadDocument:output_type -> docai.v1.DownloadDocumentResponse
+	1,  // 33: docai.v1.DocumentService.AnalyzeDocument:output_type -> docai.v1.Document
+	13, // 34: docai.v1.DocumentService.AnalyzeBatch:output_type -> docai.v1.AnalysisBatch
+	13, // 35: docai.v1.DocumentService.GetBatch:output_type -> docai.v1.AnalysisBatch
+	18, // 36: docai.v1.DocumentService.WatchDocument:output_type -> docai.v1.DocumentEvent
+	18, // 37: docai.v1.DocumentService.WatchEvents:output_type -> docai.v1.DocumentEvent
+	29, // [29:38] is the sub-list for method output_type
+	20, // [20:29] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
+}
+
+func init() { file_docai_v1_documents_proto_init() }
+func file_docai_v1_documents_proto_init() {
+	if File_docai_v1_documents_proto != nil {
+		return
+	}
+	file_docai_v1_documents_proto_msgTypes[1].OneofWrappers = []any{
+		(*UploadDocumentRequest_Metadata)(nil),
+		(*UploadDocumentRequest_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_docai_v1_documents_proto_rawDesc), len(file_docai_v1_documents_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_docai_v1_documents_proto_goTypes,
+		DependencyIndexes: file_docai_v1_documents_proto_depIdxs,
+		EnumInfos:         file_docai_v1_documents_proto_enumTypes,
+		MessageInfos:      file_docai_v1_documents_proto_msgTypes,
+	}.Build()
+	File_docai_v1_documents_proto = out.File
+	file_docai_v1_documents_proto_goTypes = nil
+	file_docai_v1_documents_proto_depIdxs = nil
+}
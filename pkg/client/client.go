@@ -0,0 +1,177 @@
+// Package client is a Go client for the DocAI HTTP API.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zjoart/docai/pkg/id"
+)
+
+const (
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 30 * time.Second
+)
+
+// Config configures a Client; zero values use the defaults.
+type Config struct {
+	// APIKey is sent in the X-API-Key header.
+	APIKey string
+	// Token is a JWT sent as a bearer token when there is no APIKey.
+	Token string
+	// HTTPClient defaults to a client with a two minute timeout, as
+	// analysis is synchronous.
+	HTTPClient *http.Client
+	// MaxRetries is how often a request is retried after a 5xx or 429
+	// response, a network error, or while an earlier attempt with the same
+	// Idempotency-Key is still running. Defaults to 3; negative disables retries.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry, doubled for each
+	// further one up to 30s, unless the server sends Retry-After. A
+	// Retry-After longer than 30s is not waited for; the error is returned.
+	// Defaults to 500ms.
+	RetryBackoff time.Duration
+}
+
+// Client calls the DocAI API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	config  Config
+}
+
+// NewClient creates a client for the API at baseURL, e.g.
+// "http://localhost:8080".
+func NewClient(baseURL string, config Config) *Client {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 2 * time.Minute}
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = defaultMaxRetries
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = defaultRetryBackoff
+	}
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), config: config}
+}
+
+// request is an API call. The body is kept in memory so it can be sent
+// again on a retry.
+type request struct {
+	method      string
+	path        string
+	query       url.Values
+	contentType string
+	body        []byte
+	// idempotencyKey is sent with every attempt, so a retry of a request
+	// the server already handled gets the original response.
+	idempotencyKey string
+}
+
+// idempotencyKey returns key, or a new random key if it is empty.
+func idempotencyKey(key string) string {
+	if key != "" {
+		return key
+	}
+	return id.Generate()
+}
+
+// do sends the request, retrying on 5xx, 429 and network errors, and
+// decodes a successful response into out.
+func (c *Client) do(ctx context.Context, req request, out interface{}) error {
+	backoff := c.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, req)
+		if err == nil && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+			if out == nil {
+				return nil
+			}
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return fmt.Errorf("docai: failed to decode response: %w", err)
+			}
+			return nil
+		}
+
+		wait := backoff
+		if err == nil {
+			apiErr := newError(resp)
+			if !apiErr.retryable() {
+				return apiErr
+			}
+			if apiErr.RetryAfter > maxRetryBackoff {
+				// e.g. a used-up daily quota; don't hold the call for hours
+				return apiErr
+			}
+			if apiErr.RetryAfter > 0 {
+				wait = apiErr.RetryAfter
+			}
+			err = apiErr
+		} else if ctx.Err() != nil {
+			return err
+		}
+
+		if attempt >= c.config.MaxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
+}
+
+func (c *Client) send(ctx context.Context, req request) (*http.Response, error) {
+	target := c.baseURL + req.path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if req.contentType != "" {
+		httpReq.Header.Set("Content-Type", req.contentType)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if req.idempotencyKey != "" {
+		httpReq.Header.Set("Idempotency-Key", req.idempotencyKey)
+	}
+	switch {
+	case c.config.APIKey != "":
+		httpReq.Header.Set("X-API-Key", c.config.APIKey)
+	case c.config.Token != "":
+		httpReq.Header.Set("Authorization", "Bearer "+c.config.Token)
+	}
+
+	return c.config.HTTPClient.Do(httpReq)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as a date.
+func retryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
@@ -0,0 +1,188 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUploaded   Status = "uploaded"
+	StatusQueued     Status = "queued"
+	StatusProcessing Status = "processing"
+	StatusAnalyzed   Status = "analyzed"
+	StatusFailed     Status = "failed"
+	StatusRejected   Status = "rejected"
+)
+
+// Pending reports whether analysis is queued or running.
+func (s Status) Pending() bool {
+	return s == StatusQueued || s == StatusProcessing
+}
+
+type Document struct {
+	ID            string          `json:"id"`
+	TenantID      string          `json:"tenant_id"`
+	OwnerID       string          `json:"owner_id,omitempty"`
+	Filename      string          `json:"filename"`
+	FileURL       string          `json:"file_url,omitempty"`
+	ContentType   string          `json:"content_type"`
+	SizeBytes     int64           `json:"size_bytes"`
+	ExtractedText string          `json:"extracted_text,omitempty"`
+	Summary       string          `json:"summary"`
+	DocType       string          `json:"doc_type"`
+	Metadata      json.RawMessage `json:"metadata,omitempty"`
+	Status        Status          `json:"status"`
+	FailureReason string          `json:"failure_reason,omitempty"`
+	Attempts      int             `json:"attempts"`
+	Version       int             `json:"version"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+type UploadOptions struct {
+	// Analyze queues the document for analysis straight after upload.
+	Analyze bool
+	// IdempotencyKey identifies the upload so that sending it again returns
+	// the first result instead of a duplicate. Retries within one call always
+	// share a key; set this to also cover retries across calls. Defaults to
+	// a random key.
+	IdempotencyKey string
+}
+
+// ListOptions narrows List; zero values list the newest documents.
+type ListOptions struct {
+	Status  Status
+	DocType string
+	Limit   int
+	Offset  int
+}
+
+// Upload sends a document read from r under the given filename.
+func (c *Client) Upload(ctx context.Context, filename string, r io.Reader, opts UploadOptions) (*Document, error) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, r); err != nil {
+		return nil, fmt.Errorf("docai: failed to read %s: %w", filename, err)
+	}
+	if opts.Analyze {
+		writer.WriteField("processImmediately", "true")
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Document Document `json:"document"`
+	}
+	err = c.do(ctx, request{
+		method:         http.MethodPost,
+		path:           "/documents/upload",
+		contentType:    writer.FormDataContentType(),
+		body:           body.Bytes(),
+		idempotencyKey: idempotencyKey(opts.IdempotencyKey),
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Document, nil
+}
+
+// UploadFile uploads the file at path under its base name.
+func (c *Client) UploadFile(ctx context.Context, path string, opts UploadOptions) (*Document, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return c.Upload(ctx, filepath.Base(path), file, opts)
+}
+
+// Analyze runs analysis of a document and returns it once done. Retries
+// after a timeout don't start a second analysis.
+func (c *Client) Analyze(ctx context.Context, id string) (*Document, error) {
+	var doc Document
+	req := request{
+		method:         http.MethodPost,
+		path:           "/documents/" + url.PathEscape(id) + "/analyze",
+		idempotencyKey: idempotencyKey(""),
+	}
+	if err := c.do(ctx, req, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (c *Client) Get(ctx context.Context, id string) (*Document, error) {
+	var doc Document
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/documents/" + url.PathEscape(id)}, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// List returns the caller's documents, newest first. The extracted text is
+// not included; Get a document to read it.
+func (c *Client) List(ctx context.Context, opts ListOptions) ([]Document, error) {
+	query := url.Values{}
+	if opts.Status != "" {
+		query.Set("status", string(opts.Status))
+	}
+	if opts.DocType != "" {
+		query.Set("doc_type", opts.DocType)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		query.Set("offset", strconv.Itoa(opts.Offset))
+	}
+
+	var list []Document
+	if err := c.do(ctx, request{method: http.MethodGet, path: "/documents", query: query}, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// WaitForAnalysis polls a document every interval until it is no longer
+// queued or processing. If analysis failed the document is returned with an
+// error matching ErrAnalysisFailed. Bound the wait with ctx.
+func (c *Client) WaitForAnalysis(ctx context.Context, id string, interval time.Duration) (*Document, error) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	for {
+		doc, err := c.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if doc.Status == StatusFailed {
+			return doc, fmt.Errorf("%w: %s", ErrAnalysisFailed, doc.FailureReason)
+		}
+		if !doc.Status.Pending() {
+			return doc, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return doc, fmt.Errorf("docai: gave up waiting for document %s in status %s: %w", id, doc.Status, ctx.Err())
+		case <-time.After(interval):
+		}
+	}
+}
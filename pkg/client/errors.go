@@ -0,0 +1,154 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Errors that an *Error matches with errors.Is, by response status or, for
+// the last three, by error code.
+var (
+	ErrBadRequest      = errors.New("docai: bad request")
+	ErrUnauthorized    = errors.New("docai: unauthorized")
+	ErrForbidden       = errors.New("docai: forbidden")
+	ErrNotFound        = errors.New("docai: not found")
+	ErrConflict        = errors.New("docai: conflict")
+	ErrTooLarge        = errors.New("docai: upload too large")
+	ErrUnsupportedType = errors.New("docai: unsupported file type")
+	ErrRateLimited     = errors.New("docai: rate limited")
+	ErrUnavailable     = errors.New("docai: server error")
+	// ErrExtractionFailed means no text could be read from the upload.
+	ErrExtractionFailed = errors.New("docai: text extraction failed")
+	// ErrMalwareDetected means the upload failed the malware scan.
+	ErrMalwareDetected = errors.New("docai: malware detected")
+	// ErrQuotaExceeded means a tenant quota is used up. It is returned
+	// without retrying when the quota resets later than the client would
+	// wait, e.g. a daily analysis quota.
+	ErrQuotaExceeded = errors.New("docai: quota exceeded")
+)
+
+// ErrAnalysisFailed is returned by WaitForAnalysis, together with the
+// document, when analysis ended in failure.
+var ErrAnalysisFailed = errors.New("docai: analysis failed")
+
+// Error codes the API reports in Error.Code. Codes are stable; new ones may
+// be added.
+const (
+	CodeBadRequest       = "bad_request"
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeTooLarge         = "too_large"
+	CodeUnsupported      = "unsupported_type"
+	CodeExtractionFailed = "extraction_failed"
+	CodeMalwareDetected  = "malware_detected"
+	CodeQuotaExceeded    = "quota_exceeded"
+	CodeInternal         = "internal"
+	CodeUpstreamLLM      = "upstream_llm"
+	CodeUnavailable      = "unavailable"
+
+	// CodeIdempotencyKeyInUse is retried by the client; it means a request
+	// with the same key is still running.
+	CodeIdempotencyKeyInUse  = "idempotency_key_in_use"
+	CodeIdempotencyKeyReused = "idempotency_key_reused"
+)
+
+// Error is a non-2xx response from the API, read from its RFC 7807 problem
+// details.
+type Error struct {
+	StatusCode int
+	// Code is the stable error code, e.g. "not_found". It is empty for
+	// responses that carry none, such as those of a proxy.
+	Code string
+	// Message is the API's explanation, e.g. "Document not found".
+	Message string
+	// RequestID identifies the request in the server's logs.
+	RequestID string
+	// RetryAfter is the wait the server asked for, if any.
+	RetryAfter time.Duration
+}
+
+func newError(resp *http.Response) *Error {
+	defer resp.Body.Close()
+
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+		RetryAfter: retryAfter(resp.Header.Get("Retry-After")),
+	}
+	// servers before problem details answered with {"message": ...}
+	var body struct {
+		Title     string `json:"title"`
+		Detail    string `json:"detail"`
+		Code      string `json:"code"`
+		RequestID string `json:"request_id"`
+		Message   string `json:"message"`
+	}
+	if json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body) == nil {
+		e.Code = body.Code
+		e.Message = firstNonEmpty(body.Detail, body.Message, body.Title)
+		if body.RequestID != "" {
+			e.RequestID = body.RequestID
+		}
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("docai: %d %s (request %s)", e.StatusCode, e.Message, e.RequestID)
+	}
+	return fmt.Sprintf("docai: %d %s", e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel error for the response status.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrTooLarge:
+		return e.StatusCode == http.StatusRequestEntityTooLarge
+	case ErrUnsupportedType:
+		return e.StatusCode == http.StatusUnsupportedMediaType
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnavailable:
+		return e.StatusCode >= 500
+	case ErrExtractionFailed:
+		return e.Code == CodeExtractionFailed
+	case ErrMalwareDetected:
+		return e.Code == CodeMalwareDetected
+	case ErrQuotaExceeded:
+		return e.Code == CodeQuotaExceeded
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (e *Error) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500 || e.Code == CodeIdempotencyKeyInUse
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type contextKey int
+
+const (
+	requestIDContextKey contextKey = iota
+	documentIDContextKey
+)
+
+// WithRequestID stores the request ID that FromContext attaches to entries.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// WithDocumentID stores the document ID that FromContext attaches to entries.
+func WithDocumentID(ctx context.Context, documentID string) context.Context {
+	return context.WithValue(ctx, documentIDContextKey, documentID)
+}
+
+func DocumentIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(documentIDContextKey).(string)
+	return id
+}
+
+// Inherit copies the request and document IDs of src into dst, so work
+// handed to a background context keeps logging under the same IDs.
+func Inherit(dst, src context.Context) context.Context {
+	if id := RequestIDFromContext(src); id != "" {
+		dst = WithRequestID(dst, id)
+	}
+	if id := DocumentIDFromContext(src); id != "" {
+		dst = WithDocumentID(dst, id)
+	}
+	return dst
+}
+
+// Logger writes entries that carry a fixed set of context fields.
+type Logger struct {
+	zap *zap.Logger
+}
+
+// FromContext returns a logger that adds the request and document IDs
+// stored in ctx, and the current trace ID, to every entry.
+func FromContext(ctx context.Context) *Logger {
+	l := Log
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		l = l.With(zap.String(TraceIDKey, sc.TraceID().String()))
+	}
+	if id := RequestIDFromContext(ctx); id != "" {
+		l = l.With(zap.String(RequestIDKey, id))
+	}
+	if id := DocumentIDFromContext(ctx); id != "" {
+		l = l.With(zap.String(DocumentIDKey, id))
+	}
+	return &Logger{zap: l}
+}
+
+func (l *Logger) Info(msg string, fields ...Fields) {
+	l.zap.Info(msg, contextZapFields(fields)...)
+}
+
+func (l *Logger) Error(msg string, fields ...Fields) {
+	l.zap.Error(msg, contextZapFields(fields)...)
+}
+
+func (l *Logger) Debug(msg string, fields ...Fields) {
+	l.zap.Debug(msg, contextZapFields(fields)...)
+}
+
+func (l *Logger) Warn(msg string, fields ...Fields) {
+	l.zap.Warn(msg, contextZapFields(fields)...)
+}
+
+func contextZapFields(fields []Fields) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	return getZapFields(fields[0])
+}
@@ -11,6 +11,7 @@ var Log *zap.Logger
 const (
 	RequestIDKey  = "request_id"
 	DocumentIDKey = "document_id"
+	TraceIDKey    = "trace_id"
 	ServiceKey    = "service"
 	EnvKey        = "env"
 	ErrorKey      = "error"
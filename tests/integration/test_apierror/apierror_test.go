@@ -0,0 +1,84 @@
+package test_apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zjoart/docai/internal/apierror"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode problem: %v", err)
+	}
+	return body
+}
+
+func TestWriteProblem(t *testing.T) {
+	req := httptest.NewRequest("GET", "/documents/abc", nil)
+	req = req.WithContext(logger.WithRequestID(req.Context(), "req-1"))
+	w := httptest.NewRecorder()
+
+	err := &apierror.Error{
+		Code:       apierror.CodeQuotaExceeded,
+		Message:    "Quota exceeded: daily_analyses",
+		RetryAfter: 1500 * time.Millisecond,
+		Details:    map[string]interface{}{"limit": "daily_analyses", "status": "ignored"},
+	}
+	apierror.Write(w, req, err)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Content-Type"); got != apierror.ContentType {
+		t.Errorf("Expected problem content type, got %q", got)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After rounded up to 2, got %q", got)
+	}
+
+	body := decode(t, w)
+	if body["code"] != "quota_exceeded" || body["type"] != apierror.TypePrefix+"quota_exceeded" {
+		t.Errorf("Unexpected code or type: %v", body)
+	}
+	if body["detail"] != "Quota exceeded: daily_analyses" || body["instance"] != "/documents/abc" || body["request_id"] != "req-1" {
+		t.Errorf("Unexpected members: %v", body)
+	}
+	if body["limit"] != "daily_analyses" || body["status"] != float64(http.StatusTooManyRequests) {
+		t.Errorf("Expected extensions without overriding status: %v", body)
+	}
+}
+
+func TestUntypedErrorsAreHidden(t *testing.T) {
+	w := httptest.NewRecorder()
+	apierror.Write(w, httptest.NewRequest("GET", "/", nil), errors.New("pq: password authentication failed"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", w.Code)
+	}
+	body := decode(t, w)
+	if body["code"] != "internal" || body["detail"] != "Internal server error" {
+		t.Errorf("Expected a generic internal error, got %v", body)
+	}
+}
+
+func TestWrappedErrorsKeepTheirCode(t *testing.T) {
+	cause := errors.New("boom")
+	err := apierror.Wrap(apierror.CodeUpstreamLLM, "Analysis failed", cause)
+	wrapped := errors.Join(errors.New("context"), err)
+
+	e := apierror.From(wrapped)
+	if e.Code != apierror.CodeUpstreamLLM || !errors.Is(wrapped, cause) {
+		t.Errorf("Expected the typed error to be found, got %v", e)
+	}
+	if e.Code.HTTPStatus() != http.StatusBadGateway {
+		t.Errorf("Expected 502, got %d", e.Code.HTTPStatus())
+	}
+}
@@ -0,0 +1,155 @@
+package test_auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/zjoart/docai/internal/auth"
+)
+
+func TestAPIKeyLifecycle(t *testing.T) {
+
+	env := SetupTestEnv(t)
+	r := env.Router
+
+	probe := func(credential string) int {
+		req := httptest.NewRequest("GET", "/probe", nil)
+		if credential != "" {
+			req.Header.Set("Authorization", "Bearer "+credential)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := probe(""); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without credentials, got %d", code)
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{"name": "integration", "scopes": []string{"read"}})
+	req := httptest.NewRequest("POST", "/admin/api-keys", bytes.NewReader(body))
+	req.Header.Set(auth.APIKeyHeader, bootstrapKey)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Create key failed: status %d, body: %s", w.Code, w.Body.String())
+	}
+
+	var created struct {
+		APIKey auth.APIKey `json:"api_key"`
+		Key    string      `json:"key"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode key: %v", err)
+	}
+
+	if code := probe(created.Key); code != http.StatusNoContent {
+		t.Errorf("Expected new key to be accepted, got %d", code)
+	}
+
+	listReq := httptest.NewRequest("GET", "/admin/api-keys", nil)
+	listReq.Header.Set(auth.APIKeyHeader, created.Key)
+	listW := httptest.NewRecorder()
+	r.ServeHTTP(listW, listReq)
+
+	if listW.Code != http.StatusForbidden {
+		t.Errorf("Expected read-only key to be forbidden from admin routes, got %d", listW.Code)
+	}
+
+	revokeReq := httptest.NewRequest("DELETE", fmt.Sprintf("/admin/api-keys/%s", created.APIKey.ID), nil)
+	revokeReq.Header.Set(auth.APIKeyHeader, bootstrapKey)
+	revokeW := httptest.NewRecorder()
+	r.ServeHTTP(revokeW, revokeReq)
+
+	if revokeW.Code != http.StatusNoContent {
+		t.Fatalf("Revoke failed: status %d, body: %s", revokeW.Code, revokeW.Body.String())
+	}
+
+	if code := probe(created.Key); code != http.StatusUnauthorized {
+		t.Errorf("Expected revoked key to be rejected, got %d", code)
+	}
+}
+
+func TestJWTAuthentication(t *testing.T) {
+
+	env := SetupTestEnv(t)
+	r := env.Router
+
+	sign := func(claims jwt.MapClaims, secret string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+		return token
+	}
+
+	cases := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"valid", sign(jwt.MapClaims{"sub": "svc-a", "tenant_id": "default", "scope": "read", "exp": time.Now().Add(time.Hour).Unix()}, jwtSecret), http.StatusNoContent},
+		{"missing scope", sign(jwt.MapClaims{"sub": "svc-a", "tenant_id": "default", "scope": "write", "exp": time.Now().Add(time.Hour).Unix()}, jwtSecret), http.StatusForbidden},
+		{"expired", sign(jwt.MapClaims{"sub": "svc-a", "tenant_id": "default", "scope": "read", "exp": time.Now().Add(-time.Hour).Unix()}, jwtSecret), http.StatusUnauthorized},
+		{"missing tenant", sign(jwt.MapClaims{"sub": "svc-a", "scope": "read", "exp": time.Now().Add(time.Hour).Unix()}, jwtSecret), http.StatusUnauthorized},
+		{"system tenant", sign(jwt.MapClaims{"sub": "svc-a", "tenant_id": "*", "scope": "admin", "exp": time.Now().Add(time.Hour).Unix()}, jwtSecret), http.StatusUnauthorized},
+		{"wrong secret", sign(jwt.MapClaims{"sub": "svc-a", "tenant_id": "default", "scope": "read", "exp": time.Now().Add(time.Hour).Unix()}, "other"), http.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/probe", nil)
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.status {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.status, w.Code)
+		}
+	}
+}
+
+// TestSystemTenantTokenIsRejected needs no database: the token must be
+// refused before any query could run in the system scope.
+func TestSystemTenantTokenIsRejected(t *testing.T) {
+	svc, err := auth.NewService(nil, nil, auth.Config{JWT: auth.JWTConfig{Secret: jwtSecret}})
+	if err != nil {
+		t.Fatalf("Failed to create auth service: %v", err)
+	}
+	reached := false
+	h := svc.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { reached = true }))
+
+	for _, tenantID := range []string{"*", "Acme Corp", "default"} {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub": "svc-a", "tenant_id": tenantID, "scope": "admin", "exp": time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte(jwtSecret))
+		if err != nil {
+			t.Fatalf("Failed to sign token: %v", err)
+		}
+
+		reached = false
+		req := httptest.NewRequest("GET", "/documents", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		want := http.StatusUnauthorized
+		if tenantID == "default" {
+			want = http.StatusOK
+		}
+		if w.Code != want || reached != (want == http.StatusOK) {
+			t.Errorf("tenant_id %q: expected %d, got %d", tenantID, want, w.Code)
+		}
+	}
+
+	// a principal can never be turned into the system scope
+	if _, err := (&auth.Principal{Subject: "x", TenantID: "*"}).TenantScope(); err == nil {
+		t.Errorf("Expected no scope for the system tenant")
+	}
+}
@@ -0,0 +1,55 @@
+package test_auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+	"github.com/zjoart/docai/internal/auth"
+	"github.com/zjoart/docai/internal/config"
+	"github.com/zjoart/docai/internal/database"
+	"github.com/zjoart/docai/internal/tenant"
+)
+
+const (
+	bootstrapKey = "integration-bootstrap-key"
+	jwtSecret    = "integration-jwt-secret"
+)
+
+type TestEnv struct {
+	Router http.Handler
+}
+
+func SetupTestEnv(t *testing.T) *TestEnv {
+
+	_ = godotenv.Load("../../../.env")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.Connect(cfg.DBURL)
+	if err != nil {
+		t.Fatalf("DB connect failed: %v", err)
+	}
+
+	svc, err := auth.NewService(auth.NewRepository(db), tenant.NewRepository(db), auth.Config{
+		BootstrapKey: bootstrapKey,
+		JWT:          auth.JWTConfig{Secret: jwtSecret},
+	})
+	if err != nil {
+		t.Fatalf("Auth init failed: %v", err)
+	}
+
+	r := mux.NewRouter()
+	api := r.NewRoute().Subrouter()
+	api.Use(svc.Middleware)
+	auth.RegisterRoutes(api, auth.NewHandler(svc))
+	api.HandleFunc("/probe", auth.RequireScope(auth.ScopeRead, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})).Methods("GET")
+
+	return &TestEnv{Router: r}
+}
@@ -0,0 +1,210 @@
+package test_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/zjoart/docai/pkg/client"
+)
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func newClient(url string) *client.Client {
+	return client.NewClient(url, client.Config{APIKey: "test-key", RetryBackoff: time.Millisecond})
+}
+
+func TestUploadSendsFileAndRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "test-key" {
+			t.Errorf("Missing API key")
+		}
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("Expected a multipart upload: %v", err)
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil || header.Filename != "note.txt" {
+			t.Fatalf("Expected note.txt, got %v, %v", header, err)
+		}
+		file.Close()
+		if r.FormValue("processImmediately") != "true" {
+			t.Errorf("Expected processImmediately")
+		}
+
+		// the body must be sent again in full on a retry
+		if calls.Add(1) == 1 {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"message": "try again"})
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"message":  "ok",
+			"document": map[string]string{"id": "doc-1", "filename": header.Filename, "status": "queued"},
+		})
+	}))
+	defer srv.Close()
+
+	doc, err := newClient(srv.URL).Upload(context.Background(), "note.txt", strings.NewReader("hello"), client.UploadOptions{Analyze: true})
+	if err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+	if doc.ID != "doc-1" || doc.Status != client.StatusQueued {
+		t.Errorf("Unexpected document: %+v", doc)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected one retry, got %d calls", calls.Load())
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("Expected the retry to reuse the Idempotency-Key, got %q", keys)
+	}
+}
+
+func TestErrorsAreTyped(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/documents/missing":
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Document not found"})
+		case "/documents/limited":
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusTooManyRequests, map[string]string{"message": "slow down"})
+		}
+	}))
+	defer srv.Close()
+
+	c := client.NewClient(srv.URL, client.Config{MaxRetries: -1})
+	_, err := c.Get(context.Background(), "missing")
+	var apiErr *client.Error
+	if !errors.Is(err, client.ErrNotFound) || !errors.As(err, &apiErr) || apiErr.Message != "Document not found" {
+		t.Fatalf("Expected a not found error, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("A 404 must not be retried, got %d calls", calls.Load())
+	}
+
+	_, err = c.Get(context.Background(), "limited")
+	if !errors.Is(err, client.ErrRateLimited) || !errors.As(err, &apiErr) || apiErr.RetryAfter != time.Second {
+		t.Fatalf("Expected a rate limit error with Retry-After, got %v", err)
+	}
+}
+
+func TestProblemDetailsAreRead(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"type":       "urn:docai:problem:extraction_failed",
+			"title":      "Unprocessable Entity",
+			"status":     http.StatusUnprocessableEntity,
+			"detail":     "Failed to extract text",
+			"code":       "extraction_failed",
+			"request_id": "req-42",
+		})
+	}))
+	defer srv.Close()
+
+	_, err := newClient(srv.URL).Analyze(context.Background(), "doc-1")
+	var apiErr *client.Error
+	if !errors.Is(err, client.ErrExtractionFailed) || !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an extraction error, got %v", err)
+	}
+	if apiErr.Code != client.CodeExtractionFailed || apiErr.Message != "Failed to extract text" || apiErr.RequestID != "req-42" {
+		t.Errorf("Unexpected error fields: %+v", apiErr)
+	}
+}
+
+func TestRetriesStopWithContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "20")
+		writeJSON(w, http.StatusTooManyRequests, map[string]string{"message": "slow down"})
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if _, err := newClient(srv.URL).Get(ctx, "doc-1"); !errors.Is(err, client.ErrRateLimited) {
+		t.Fatalf("Expected the last rate limit error, got %v", err)
+	}
+	if time.Since(started) > 5*time.Second {
+		t.Errorf("Retry wait ignored the context")
+	}
+}
+
+func TestLongRetryAfterIsNotWaitedFor(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		// a daily quota resets hours from now
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.Header().Set("Retry-After", "36000")
+		w.WriteHeader(http.StatusTooManyRequests)
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": http.StatusTooManyRequests, "code": "quota_exceeded", "detail": "Daily analysis quota exceeded"})
+	}))
+	defer srv.Close()
+
+	started := time.Now()
+	_, err := newClient(srv.URL).Analyze(context.Background(), "doc-1")
+	if !errors.Is(err, client.ErrQuotaExceeded) || !errors.Is(err, client.ErrRateLimited) {
+		t.Fatalf("Expected a quota error, got %v", err)
+	}
+	if calls.Load() != 1 || time.Since(started) > 5*time.Second {
+		t.Errorf("Expected no retry, got %d calls in %s", calls.Load(), time.Since(started))
+	}
+}
+
+func TestListSendsFilters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/documents" || q.Get("status") != "analyzed" || q.Get("doc_type") != "invoice" || q.Get("limit") != "10" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		writeJSON(w, http.StatusOK, []map[string]string{{"id": "a"}, {"id": "b"}})
+	}))
+	defer srv.Close()
+
+	list, err := newClient(srv.URL).List(context.Background(), client.ListOptions{Status: client.StatusAnalyzed, DocType: "invoice", Limit: 10})
+	if err != nil || len(list) != 2 {
+		t.Fatalf("Expected two documents, got %v, %v", list, err)
+	}
+}
+
+func TestWaitForAnalysis(t *testing.T) {
+	var polls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := "processing"
+		if polls.Add(1) >= 3 {
+			status = "analyzed"
+		}
+		if r.URL.Path == "/documents/broken" {
+			writeJSON(w, http.StatusOK, map[string]string{"id": "broken", "status": "failed", "failure_reason": "LLM error"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"id": "doc-1", "status": status})
+	}))
+	defer srv.Close()
+
+	c := newClient(srv.URL)
+	doc, err := c.WaitForAnalysis(context.Background(), "doc-1", time.Millisecond)
+	if err != nil || doc.Status != client.StatusAnalyzed || polls.Load() != 3 {
+		t.Fatalf("Expected analyzed after three polls, got %+v, %v after %d", doc, err, polls.Load())
+	}
+
+	doc, err = c.WaitForAnalysis(context.Background(), "broken", time.Millisecond)
+	if !errors.Is(err, client.ErrAnalysisFailed) || doc == nil || doc.FailureReason != "LLM error" {
+		t.Fatalf("Expected a failed analysis, got %+v, %v", doc, err)
+	}
+}
@@ -0,0 +1,101 @@
+package test_config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zjoart/docai/internal/config"
+)
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+func setRequired(t *testing.T) {
+	t.Setenv("DATABASE_URL", "postgres://docai:hunter2@db:5432/docai")
+	t.Setenv("MINIO_ENDPOINT", "minio:9000")
+	t.Setenv("MINIO_ACCESS_KEY", "access")
+	t.Setenv("MINIO_SECRET_KEY", "minio-secret")
+	t.Setenv("MINIO_BUCKET", "documents")
+	t.Setenv("OPENROUTER_API_KEY", "sk-or-secret")
+}
+
+func TestFlagsOverrideEnvOverridesFile(t *testing.T) {
+	setRequired(t)
+	path := writeFile(t, "batch_workers: 2\nllm_model: from-file\nscan_timeout: 45s\nport: 9090\n")
+	t.Setenv("BATCH_WORKERS", "6")
+
+	cfg, err := config.Load("--config", path, "--llm-model", "from-flag")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if cfg.BatchWorkers != 6 {
+		t.Errorf("Expected env to override the file, got %d workers", cfg.BatchWorkers)
+	}
+	if cfg.LLMModel != "from-flag" {
+		t.Errorf("Expected the flag to win, got model %q", cfg.LLMModel)
+	}
+	if cfg.ScanTimeout != 45*time.Second || cfg.Port != "9090" {
+		t.Errorf("Expected file values, got scan timeout %s and port %q", cfg.ScanTimeout, cfg.Port)
+	}
+	if cfg.ShutdownTimeout != config.Default().ShutdownTimeout {
+		t.Errorf("Expected the default shutdown timeout, got %s", cfg.ShutdownTimeout)
+	}
+}
+
+func TestValidationReportsEveryError(t *testing.T) {
+	t.Setenv("BATCH_WORKERS", "many")
+	t.Setenv("PII_MODE", "shout")
+
+	_, err := config.Load("--port", "http")
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+
+	for _, want := range []string{"BATCH_WORKERS", "database_url", "openrouter_api_key", "port", "pii_mode"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected an error about %s in:\n%v", want, err)
+		}
+	}
+}
+
+func TestUnknownFileKeysAreRejected(t *testing.T) {
+	setRequired(t)
+	path := writeFile(t, "batch_wrokers: 2\n")
+
+	if _, err := config.Load("--config", path); err == nil || !strings.Contains(err.Error(), "batch_wrokers") {
+		t.Fatalf("Expected the misspelled key to be reported, got %v", err)
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	setRequired(t)
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Print(&out); err != nil {
+		t.Fatalf("Print failed: %v", err)
+	}
+
+	for _, secret := range []string{"hunter2", "minio-secret", "sk-or-secret"} {
+		if strings.Contains(out.String(), secret) {
+			t.Errorf("Secret %q leaked:\n%s", secret, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), "db:5432") || !strings.Contains(out.String(), "scan_timeout: 30s") {
+		t.Errorf("Expected readable non-secret values:\n%s", out.String())
+	}
+}
@@ -0,0 +1,47 @@
+package test_database
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/zjoart/docai/internal/database"
+	"github.com/zjoart/docai/migrations"
+)
+
+func TestEmbeddedMigrationsAreComplete(t *testing.T) {
+	loaded, err := database.LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("Failed to load embedded migrations: %v", err)
+	}
+	if len(loaded) == 0 {
+		t.Fatal("No migrations embedded")
+	}
+
+	for i, m := range loaded {
+		if m.Version != i+1 {
+			t.Errorf("Expected version %d, got %06d_%s", i+1, m.Version, m.Name)
+		}
+		if m.Down == "" {
+			t.Errorf("Migration %06d_%s has no down script", m.Version, m.Name)
+		}
+	}
+}
+
+func TestMigrationWithoutUpScriptIsRejected(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_init.up.sql":     {Data: []byte("CREATE TABLE a (id int);")},
+		"000001_init.down.sql":   {Data: []byte("DROP TABLE a;")},
+		"000002_broken.down.sql": {Data: []byte("DROP TABLE b;")},
+		"README.md":              {Data: []byte("not a migration")},
+	}
+
+	if _, err := database.LoadMigrations(fsys); err == nil {
+		t.Fatal("Expected an error for a migration without an up script")
+	}
+
+	delete(fsys, "000002_broken.down.sql")
+	loaded, err := database.LoadMigrations(fsys)
+	if err != nil || len(loaded) != 1 || loaded[0].Name != "init" {
+		t.Fatalf("Expected only the init migration, got %+v, %v", loaded, err)
+	}
+}
@@ -53,8 +53,8 @@ func TestDocumentFlow(t *testing.T) {
 
 	t.Logf("Uploaded Doc ID: %s", doc.ID)
 
-	if doc.FileUrl == "" {
-		t.Error("Expected FileUrl to be set")
+	if want := "/documents/" + doc.ID.String() + "/download"; doc.FileUrl != want {
+		t.Errorf("Expected FileUrl %q, got %q", want, doc.FileUrl)
 	}
 
 	{
@@ -0,0 +1,122 @@
+package test_documents
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/documents"
+	"github.com/zjoart/docai/internal/requestlog"
+)
+
+func TestAuditTrailForDocument(t *testing.T) {
+
+	env := SetupTestEnv(t)
+	r := env.Router
+
+	content := []byte("Audit trail check.")
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	filename := fmt.Sprintf("test_%s.txt", uuid.New().String())
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/documents/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set(requestlog.RequestIDHeader, "audit-test-upload")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Upload failed: status %d, body: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get(requestlog.RequestIDHeader); got != "audit-test-upload" {
+		t.Errorf("Expected request ID to be echoed, got %q", got)
+	}
+
+	var respData struct {
+		Document documents.Document `json:"document"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &respData)
+	doc := respData.Document
+
+	wGet := httptest.NewRecorder()
+	r.ServeHTTP(wGet, httptest.NewRequest("GET", "/documents/"+doc.ID.String(), nil))
+	if wGet.Code != http.StatusOK {
+		t.Fatalf("Get failed: status %d", wGet.Code)
+	}
+
+	wDownload := httptest.NewRecorder()
+	r.ServeHTTP(wDownload, httptest.NewRequest("GET", fmt.Sprintf("/documents/%s/download", doc.ID), nil))
+	if wDownload.Code != http.StatusOK {
+		t.Fatalf("Download failed: status %d, body: %s", wDownload.Code, wDownload.Body.String())
+	}
+	if !bytes.Equal(wDownload.Body.Bytes(), content) {
+		t.Errorf("Downloaded content does not match the upload")
+	}
+
+	wAudit := httptest.NewRecorder()
+	r.ServeHTTP(wAudit, httptest.NewRequest("GET", "/audit?document_id="+doc.ID.String(), nil))
+	if wAudit.Code != http.StatusOK {
+		t.Fatalf("Audit list failed: status %d, body: %s", wAudit.Code, wAudit.Body.String())
+	}
+
+	var list []audit.Event
+	json.Unmarshal(wAudit.Body.Bytes(), &list)
+
+	seen := map[audit.Action]audit.Event{}
+	for _, e := range list {
+		seen[e.Action] = e
+	}
+	for _, action := range []audit.Action{audit.ActionUpload, audit.ActionView, audit.ActionDownload} {
+		e, ok := seen[action]
+		if !ok {
+			t.Errorf("Missing %s event, got %+v", action, list)
+			continue
+		}
+		if e.Actor != "integration-test" || e.Outcome != audit.OutcomeSuccess {
+			t.Errorf("Unexpected %s event: %+v", action, e)
+		}
+	}
+	if seen[audit.ActionUpload].RequestID != "audit-test-upload" {
+		t.Errorf("Expected upload event to carry the request ID, got %q", seen[audit.ActionUpload].RequestID)
+	}
+
+	wExport := httptest.NewRecorder()
+	r.ServeHTTP(wExport, httptest.NewRequest("GET", "/audit/export?document_id="+doc.ID.String(), nil))
+	if wExport.Code != http.StatusOK {
+		t.Fatalf("Audit export failed: status %d", wExport.Code)
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(wExport.Body)
+	for scanner.Scan() {
+		var e audit.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("Export line is not JSON: %q", scanner.Text())
+		}
+		lines++
+	}
+	if lines != len(list) {
+		t.Errorf("Expected %d exported events, got %d", len(list), lines)
+	}
+
+	// the export is recorded after it has streamed, with its outcome
+	wExports := httptest.NewRecorder()
+	r.ServeHTTP(wExports, httptest.NewRequest("GET", "/audit?action=audit.export", nil))
+	var exports []audit.Event
+	if err := json.NewDecoder(wExports.Body).Decode(&exports); err != nil || len(exports) == 0 {
+		t.Fatalf("Expected the export to be audited, got %v, %v", exports, err)
+	}
+	if exports[0].Outcome != audit.OutcomeSuccess {
+		t.Errorf("Expected a successful export event, got %+v", exports[0])
+	}
+}
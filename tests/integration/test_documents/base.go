@@ -1,15 +1,25 @@
 package test_documents
 
 import (
+	"crypto/rand"
+	"net/http"
 	"testing"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/auth"
 	"github.com/zjoart/docai/internal/config"
 	"github.com/zjoart/docai/internal/database"
 	"github.com/zjoart/docai/internal/documents"
 	"github.com/zjoart/docai/internal/documents/analyzer"
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/requestlog"
 	"github.com/zjoart/docai/internal/storage"
+	"github.com/zjoart/docai/internal/tenant"
 	"gorm.io/gorm"
 )
 
@@ -38,13 +48,22 @@ func SetupTestEnv(t *testing.T) *TestEnv {
 		t.Fatalf("Minio init failed: %v", err)
 	}
 
-	repo := documents.NewRepository(db)
-	ai := analyzer.NewAnalyzer(cfg.OpenRouterAPIKey)
-	svc := documents.NewService(repo, minioClient, ai)
-	h := documents.NewHandler(svc)
+	keys := newTestKeyring(t)
+	auditService := audit.NewService(audit.NewRepository(db))
+	bus := events.NewBus()
+	repo := documents.NewRepository(db, keys)
+	ai := analyzer.NewAnalyzer(cfg.OpenRouterAPIKey, cfg.LLMModel, nil)
+	svc := documents.NewService(repo, minioClient, ai, bus, quota.NewService(quota.NewRepository(db), tenant.NewRepository(db), quota.Limits{}), pii.NewRedactor(tenant.NewRepository(db), pii.Policy{Mode: pii.ModeRedact}), keys, nil, auditService, nil, nil)
+	batches := documents.NewBatchRunner(svc, repo, documents.BatchConfig{
+		Workers:       cfg.BatchWorkers,
+		RatePerSecond: cfg.LLMRateLimit,
+	})
+	h := documents.NewHandler(svc, batches, documents.HandlerConfig{})
 
 	r := mux.NewRouter()
+	r.Use(requestlog.Middleware, withTestPrincipal, audit.Middleware)
 	documents.RegisterRoutes(r, h)
+	audit.RegisterRoutes(r, audit.NewHandler(auditService))
 
 	return &TestEnv{
 		DB:      db,
@@ -52,3 +71,43 @@ func SetupTestEnv(t *testing.T) *TestEnv {
 		Storage: minioClient,
 	}
 }
+
+// withTestPrincipal authenticates every request as an admin of the default
+// tenant so tests can exercise routes without provisioning API keys. The
+// X-Test-Tenant header switches the tenant.
+func withTestPrincipal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := &auth.Principal{
+			Subject:  "integration-test",
+			Method:   auth.MethodBootstrap,
+			TenantID: tenant.DefaultTenant,
+			Scopes:   auth.ScopeList{auth.ScopeAdmin},
+		}
+		if t := r.Header.Get("X-Test-Tenant"); t != "" {
+			principal.TenantID = t
+		}
+
+		scope, err := principal.TenantScope()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		ctx := auth.WithPrincipal(r.Context(), principal)
+		ctx = tenant.WithScope(ctx, scope)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// newTestKeyring returns a keyring with a random master key so uploads are
+// encrypted just as in production.
+func newTestKeyring(t *testing.T) *envelope.Keyring {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Master key generation failed: %v", err)
+	}
+	keys, err := envelope.NewKeyring("test", map[string][]byte{"test": key})
+	if err != nil {
+		t.Fatalf("Keyring init failed: %v", err)
+	}
+	return keys
+}
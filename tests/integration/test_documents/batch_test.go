@@ -0,0 +1,94 @@
+package test_documents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/documents"
+)
+
+func TestBatchAnalysisFlow(t *testing.T) {
+
+	env := SetupTestEnv(t)
+	r := env.Router
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	filename := fmt.Sprintf("test_%s.txt", uuid.New().String())
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write([]byte("Invoice #42. Date: 2024-01-15. Total: $120."))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/documents/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Upload failed: status %d, body: %s", w.Code, w.Body.String())
+	}
+
+	var respData struct {
+		Document documents.Document `json:"document"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&respData); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	doc := respData.Document
+
+	batchBody, _ := json.Marshal(documents.BatchRequest{IDs: []uuid.UUID{doc.ID, uuid.New()}})
+	batchReq := httptest.NewRequest("POST", "/documents/analyze", bytes.NewReader(batchBody))
+	batchW := httptest.NewRecorder()
+	r.ServeHTTP(batchW, batchReq)
+
+	if batchW.Code != http.StatusAccepted {
+		t.Fatalf("Expected batch to be accepted, got %d. Body: %s", batchW.Code, batchW.Body.String())
+	}
+
+	var batch documents.AnalysisBatch
+	if err := json.NewDecoder(batchW.Body).Decode(&batch); err != nil {
+		t.Fatalf("Failed to decode batch response: %v", err)
+	}
+
+	if batch.Total != 1 {
+		t.Errorf("Expected unknown IDs to be dropped from the batch, got total %d", batch.Total)
+	}
+
+	deadline := time.Now().Add(60 * time.Second)
+	for batch.Status != "completed" {
+		if time.Now().After(deadline) {
+			t.Fatalf("Batch %s did not complete in time", batch.ID)
+		}
+		time.Sleep(500 * time.Millisecond)
+
+		getReq := httptest.NewRequest("GET", fmt.Sprintf("/documents/batches/%s", batch.ID), nil)
+		getW := httptest.NewRecorder()
+		r.ServeHTTP(getW, getReq)
+
+		if getW.Code != http.StatusOK {
+			t.Fatalf("Get batch failed: status %d", getW.Code)
+		}
+		if err := json.NewDecoder(getW.Body).Decode(&batch); err != nil {
+			t.Fatalf("Failed to decode batch: %v", err)
+		}
+	}
+
+	if batch.Succeeded+batch.Failed != batch.Total {
+		t.Errorf("Expected every item to be accounted for, got %d succeeded and %d failed of %d", batch.Succeeded, batch.Failed, batch.Total)
+	}
+
+	emptyReq := httptest.NewRequest("POST", "/documents/analyze", bytes.NewReader([]byte(`{}`)))
+	emptyW := httptest.NewRecorder()
+	r.ServeHTTP(emptyW, emptyReq)
+
+	if emptyW.Code != http.StatusBadRequest {
+		t.Errorf("Expected empty batch request to be rejected, got %d", emptyW.Code)
+	}
+}
@@ -0,0 +1,71 @@
+package test_documents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/documents"
+	"github.com/zjoart/docai/internal/envelope"
+)
+
+func TestExtractedTextIsEncryptedAtRest(t *testing.T) {
+
+	env := SetupTestEnv(t)
+	r := env.Router
+
+	const content = "Confidential: salary review for Jane Doe."
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	filename := fmt.Sprintf("test_%s.txt", uuid.New().String())
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/documents/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Upload failed: status %d, body: %s", w.Code, w.Body.String())
+	}
+
+	var respData struct {
+		Document documents.Document `json:"document"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &respData)
+	doc := respData.Document
+
+	var row struct {
+		ExtractedText string
+		DataKey       string
+	}
+	err := env.DB.Raw("SELECT extracted_text, data_key FROM documents WHERE id = ?", doc.ID).Scan(&row).Error
+	if err != nil {
+		t.Fatalf("Failed to read raw row: %v", err)
+	}
+	if !envelope.IsSealed(row.ExtractedText) || strings.Contains(row.ExtractedText, "Jane Doe") {
+		t.Errorf("Expected extracted_text to be encrypted in the database, got %q", row.ExtractedText)
+	}
+	if !strings.HasPrefix(row.DataKey, "test:") {
+		t.Errorf("Expected data key wrapped by the test master key, got %q", row.DataKey)
+	}
+
+	getReq := httptest.NewRequest("GET", "/documents/"+doc.ID.String(), nil)
+	wGet := httptest.NewRecorder()
+	r.ServeHTTP(wGet, getReq)
+
+	var fetched documents.Document
+	json.Unmarshal(wGet.Body.Bytes(), &fetched)
+	if fetched.ExtractedText != content {
+		t.Errorf("Expected decrypted text %q, got %q", content, fetched.ExtractedText)
+	}
+}
@@ -0,0 +1,80 @@
+package test_documents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/documents"
+)
+
+func TestListDocuments(t *testing.T) {
+
+	env := SetupTestEnv(t)
+	r := env.Router
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", fmt.Sprintf("test_%s.txt", uuid.New().String()))
+	part.Write([]byte("A document to list."))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/documents/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Upload failed: status %d, body: %s", w.Code, w.Body.String())
+	}
+	var uploaded struct {
+		Document documents.Document `json:"document"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &uploaded)
+
+	list := func(query, tenantID string) (int, []documents.Document) {
+		req := httptest.NewRequest("GET", "/documents"+query, nil)
+		if tenantID != "" {
+			req.Header.Set("X-Test-Tenant", tenantID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var docs []documents.Document
+		json.Unmarshal(w.Body.Bytes(), &docs)
+		return w.Code, docs
+	}
+
+	code, docs := list("?status=uploaded&limit=1", "")
+	if code != http.StatusOK || len(docs) != 1 || docs[0].ID != uploaded.Document.ID {
+		t.Fatalf("Expected the new upload first, got %d: %+v", code, docs)
+	}
+	if docs[0].ExtractedText != "" {
+		t.Errorf("Expected the list to leave out the extracted text, got %q", docs[0].ExtractedText)
+	}
+
+	if _, docs := list("?status=analyzed&limit=200", ""); containsDocument(docs, uploaded.Document.ID) {
+		t.Errorf("Status filter not applied")
+	}
+	if _, docs := list("", "other-tenant"); containsDocument(docs, uploaded.Document.ID) {
+		t.Errorf("Listed another tenant's document")
+	}
+
+	for _, query := range []string{"?status=bogus", "?limit=0", "?limit=1000", "?offset=-1"} {
+		if code, _ := list(query, ""); code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", query, code)
+		}
+	}
+}
+
+func containsDocument(docs []documents.Document, id uuid.UUID) bool {
+	for _, doc := range docs {
+		if doc.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package test_documents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/zjoart/docai/internal/documents"
+)
+
+func newJobService() *documents.Service {
+	return documents.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+}
+
+func TestShutdownWaitsForBackgroundJobs(t *testing.T) {
+	svc := newJobService()
+
+	finished := make(chan struct{})
+	err := svc.Go(context.Background(), func(ctx context.Context) {
+		time.Sleep(50 * time.Millisecond)
+		close(finished)
+	})
+	if err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := svc.Shutdown(ctx); err != nil {
+		t.Fatalf("Expected a clean shutdown, got %v", err)
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("Shutdown returned before the job finished")
+	}
+
+	if err := svc.Go(context.Background(), func(context.Context) {}); !errors.Is(err, documents.ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown after shutdown, got %v", err)
+	}
+}
+
+func TestShutdownInterruptsJobsPastDeadline(t *testing.T) {
+	svc := newJobService()
+
+	interrupted := make(chan error, 1)
+	err := svc.Go(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		interrupted <- ctx.Err()
+	})
+	if err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := svc.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to be exceeded, got %v", err)
+	}
+
+	select {
+	case err := <-interrupted:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the job context to be cancelled, got %v", err)
+		}
+	default:
+		t.Fatal("Shutdown returned before the interrupted job did")
+	}
+}
@@ -0,0 +1,65 @@
+package test_documents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/documents"
+	"github.com/zjoart/docai/internal/tenant"
+)
+
+func TestCrossTenantLookupIsNotFound(t *testing.T) {
+
+	env := SetupTestEnv(t)
+	r := env.Router
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	filename := fmt.Sprintf("test_%s.txt", uuid.New().String())
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write([]byte("Tenant isolation check."))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/documents/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Upload failed: status %d, body: %s", w.Code, w.Body.String())
+	}
+
+	var respData struct {
+		Document documents.Document `json:"document"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &respData)
+	doc := respData.Document
+
+	if doc.TenantID != tenant.DefaultTenant {
+		t.Errorf("Expected tenant %q, got %q", tenant.DefaultTenant, doc.TenantID)
+	}
+
+	getReq := httptest.NewRequest("GET", "/documents/"+doc.ID.String(), nil)
+	getReq.Header.Set("X-Test-Tenant", "other-tenant")
+	wGet := httptest.NewRecorder()
+	r.ServeHTTP(wGet, getReq)
+
+	if wGet.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for cross-tenant lookup, got %d: %s", wGet.Code, wGet.Body.String())
+	}
+
+	analyzeReq := httptest.NewRequest("POST", fmt.Sprintf("/documents/%s/analyze", doc.ID), nil)
+	analyzeReq.Header.Set("X-Test-Tenant", "other-tenant")
+	wAnalyze := httptest.NewRecorder()
+	r.ServeHTTP(wAnalyze, analyzeReq)
+
+	if wAnalyze.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for cross-tenant analysis, got %d: %s", wAnalyze.Code, wAnalyze.Body.String())
+	}
+}
@@ -0,0 +1,92 @@
+package test_documents
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zjoart/docai/internal/documents"
+	"github.com/zjoart/docai/internal/upload"
+)
+
+func newPolicyHandler(policy upload.Policy) *documents.Handler {
+	svc := documents.NewService(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, upload.NewPolicies(nil, policy))
+	return documents.NewHandler(svc, nil, documents.HandlerConfig{})
+}
+
+func uploadRequest(t *testing.T, filename string, size int) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(bytes.Repeat([]byte("a"), size))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/documents/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestUploadOverLimitIs413(t *testing.T) {
+	h := newPolicyHandler(upload.Policy{MaxBytes: 1 << 10, AllowedTypes: []string{upload.TypeText}})
+
+	// a body past the limit and the multipart overhead is cut off while reading
+	req := uploadRequest(t, "big.txt", 2<<20)
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	h.UploadDocument(w, req)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for a streamed oversized body, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// a file within the request limit but over its type's limit
+	w = httptest.NewRecorder()
+	h.UploadDocument(w, uploadRequest(t, "small.txt", 2<<10))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for a file over the type limit, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUploadOfDisallowedTypeIs415(t *testing.T) {
+	h := newPolicyHandler(upload.Policy{MaxBytes: 1 << 20, AllowedTypes: []string{upload.TypePDF}})
+
+	w := httptest.NewRecorder()
+	h.UploadDocument(w, uploadRequest(t, "notes.txt", 10))
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected 415, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUploadPolicyLimitsPerType(t *testing.T) {
+	policy := upload.Policy{
+		MaxBytes:       100,
+		MaxBytesByType: map[string]int64{upload.TypePDF: 1000},
+		AllowedTypes:   []string{upload.TypePDF, upload.TypeText},
+	}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Expected a valid policy, got %v", err)
+	}
+
+	if err := policy.Check("report.pdf", 500); err != nil {
+		t.Errorf("Expected the PDF limit to apply, got %v", err)
+	}
+	var tooLarge *upload.TooLargeError
+	if err := policy.Check("notes.txt", 500); !errors.As(err, &tooLarge) || tooLarge.Limit != 100 {
+		t.Errorf("Expected the default limit for text, got %v", err)
+	}
+	if err := policy.Check("slides.docx", 10); !errors.Is(err, upload.ErrTypeNotAllowed) {
+		t.Errorf("Expected DOCX to be refused, got %v", err)
+	}
+	if policy.Limit() != 1000 {
+		t.Errorf("Expected the request limit to be the largest type limit, got %d", policy.Limit())
+	}
+
+	if err := (upload.Policy{MaxBytes: 10, AllowedTypes: []string{"image/png"}}).Validate(); !errors.Is(err, upload.ErrInvalidPolicy) {
+		t.Errorf("Expected unsupported types to be rejected, got %v", err)
+	}
+}
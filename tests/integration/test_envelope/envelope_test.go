@@ -0,0 +1,88 @@
+package test_envelope
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/zjoart/docai/internal/envelope"
+)
+
+func masterKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Key generation failed: %v", err)
+	}
+	return key
+}
+
+func TestSealStringRoundTrip(t *testing.T) {
+	keys, err := envelope.NewKeyring("k1", map[string][]byte{"k1": masterKey(t)})
+	if err != nil {
+		t.Fatalf("Keyring init failed: %v", err)
+	}
+
+	dek, wrapped, err := keys.NewDataKey()
+	if err != nil {
+		t.Fatalf("NewDataKey failed: %v", err)
+	}
+
+	sealed, err := envelope.SealString(dek, "Invoice for Jane Doe", "doc:extracted_text")
+	if err != nil {
+		t.Fatalf("SealString failed: %v", err)
+	}
+	if !envelope.IsSealed(sealed) {
+		t.Fatalf("Expected sealed value, got %q", sealed)
+	}
+
+	unwrapped, err := keys.Unwrap(wrapped)
+	if err != nil {
+		t.Fatalf("Unwrap failed: %v", err)
+	}
+	plaintext, err := envelope.OpenString(unwrapped, sealed, "doc:extracted_text")
+	if err != nil || plaintext != "Invoice for Jane Doe" {
+		t.Fatalf("Expected round trip, got %q, %v", plaintext, err)
+	}
+
+	if _, err := envelope.OpenString(unwrapped, sealed, "doc:summary"); err == nil {
+		t.Error("Expected ciphertext bound to another column to be rejected")
+	}
+
+	legacy, err := envelope.OpenString(unwrapped, "plain old text", "doc:extracted_text")
+	if err != nil || legacy != "plain old text" {
+		t.Errorf("Expected plaintext to pass through, got %q, %v", legacy, err)
+	}
+}
+
+func TestRewrapAfterRotation(t *testing.T) {
+	oldKey, newKey := masterKey(t), masterKey(t)
+
+	before, _ := envelope.NewKeyring("old", map[string][]byte{"old": oldKey})
+	dek, wrapped, err := before.NewDataKey()
+	if err != nil {
+		t.Fatalf("NewDataKey failed: %v", err)
+	}
+
+	rotated, err := envelope.NewKeyring("new", map[string][]byte{"old": oldKey, "new": newKey})
+	if err != nil {
+		t.Fatalf("Keyring init failed: %v", err)
+	}
+
+	rewrapped, changed, err := rotated.Rewrap(wrapped)
+	if err != nil || !changed {
+		t.Fatalf("Expected key to be re-wrapped, got changed=%v, %v", changed, err)
+	}
+	if _, changed, _ := rotated.Rewrap(rewrapped); changed {
+		t.Error("Re-wrapping a key already under the active master key should be a no-op")
+	}
+
+	after, _ := envelope.NewKeyring("new", map[string][]byte{"new": newKey})
+	got, err := after.Unwrap(rewrapped)
+	if err != nil || string(got) != string(dek) {
+		t.Fatalf("Expected the same data key after rotation, got %v", err)
+	}
+
+	if _, err := after.Unwrap(wrapped); !errors.Is(err, envelope.ErrUnknownKey) {
+		t.Errorf("Expected ErrUnknownKey for a key wrapped by a removed master key, got %v", err)
+	}
+}
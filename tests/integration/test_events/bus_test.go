@@ -0,0 +1,54 @@
+package test_events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/zjoart/docai/internal/events"
+)
+
+func TestLosslessSubscriberGetsEveryEvent(t *testing.T) {
+	bus := events.NewBus()
+	lossy, unsubscribeLossy := bus.Subscribe(nil, 1)
+	defer unsubscribeLossy()
+	lossless, unsubscribe := bus.SubscribeLossless(nil, 1)
+	defer unsubscribe()
+
+	const count = 50
+	go func() {
+		for i := 0; i < count; i++ {
+			bus.Publish(events.Event{Type: events.DocumentAnalyzed})
+		}
+	}()
+
+	for i := 0; i < count; i++ {
+		select {
+		case <-lossless:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d events, got %d", count, i)
+		}
+	}
+	// the ordinary subscriber never read and missed all but its buffer
+	if len(lossy) != 1 {
+		t.Errorf("Expected the ordinary subscriber to keep only its buffer, got %d", len(lossy))
+	}
+}
+
+func TestUnsubscribeReleasesWaitingPublisher(t *testing.T) {
+	bus := events.NewBus()
+	_, unsubscribe := bus.SubscribeLossless(nil, 0)
+
+	published := make(chan struct{})
+	go func() {
+		bus.Publish(events.Event{Type: events.DocumentAnalyzed})
+		close(published)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	unsubscribe()
+	select {
+	case <-published:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Publish still blocked after the lossless subscriber left")
+	}
+}
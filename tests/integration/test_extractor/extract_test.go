@@ -0,0 +1,148 @@
+package test_extractor
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zjoart/docai/internal/documents/extractor"
+)
+
+// buildPDF writes a minimal PDF with one page per entry; an empty entry is a
+// page with no text.
+func buildPDF(pages []string) []byte {
+	var objects []string
+	kids := make([]string, len(pages))
+	for i, text := range pages {
+		pageObj, contentObj := 4+2*i, 5+2*i
+		kids[i] = fmt.Sprintf("%d 0 R", pageObj)
+		stream := ""
+		if text != "" {
+			stream = fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		}
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", contentObj),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+	objects = append([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}, objects...)
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+func extract(t *testing.T, format string, data []byte) *extractor.Result {
+	t.Helper()
+	result, err := extractor.Extract(format, bytes.NewReader(data), int64(len(data)), nil)
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+	return result
+}
+
+func TestExtractPDFReportsPerPageStats(t *testing.T) {
+	var progress []int
+	data := buildPDF([]string{"Invoice number 42", ""})
+	result, err := extractor.Extract(extractor.FormatPDF, bytes.NewReader(data), int64(len(data)), func(current, total int) {
+		progress = append(progress, current)
+	})
+	if err != nil {
+		t.Fatalf("Extract failed: %v", err)
+	}
+
+	if !strings.Contains(result.Text, "Invoice number 42") {
+		t.Errorf("Expected the page text, got %q", result.Text)
+	}
+	if len(result.Pages) != 2 || result.Pages[0].Words != 3 || result.Pages[1].Chars != 0 {
+		t.Errorf("Unexpected page stats: %+v", result.Pages)
+	}
+	if len(progress) != 2 {
+		t.Errorf("Expected progress for each page, got %v", progress)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "page 2") {
+		t.Errorf("Expected a warning for the empty page, got %v", result.Warnings)
+	}
+
+	// the service's PDF path must give the same text
+	text, err := extractor.ExtractTextFromPDF(bytes.NewReader(data), int64(len(data)), nil)
+	if err != nil || text != result.Text {
+		t.Errorf("ExtractTextFromPDF differs: %q, %v", text, err)
+	}
+}
+
+func TestExtractDOCX(t *testing.T) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	f, _ := zw.Create("word/document.xml")
+	f.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Hello</w:t></w:r></w:p><w:p><w:r><w:t>World</w:t></w:r></w:p></w:body></w:document>`))
+	zw.Close()
+
+	result := extract(t, extractor.FormatDOCX, buf.Bytes())
+	if strings.TrimSpace(result.Text) != "Hello\nWorld" || result.Encoding != "utf-8" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if len(result.Pages) != 1 || result.Pages[0].Words != 2 {
+		t.Errorf("Unexpected page stats: %+v", result.Pages)
+	}
+}
+
+func TestExtractTextDetectsEncoding(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     []byte
+		encoding string
+		warns    bool
+	}{
+		{"utf-8", []byte("Grüße aus Köln"), "utf-8", false},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFhello"), "utf-8 (bom)", false},
+		{"utf-16", []byte("\xFF\xFEh\x00i\x00"), "utf-16le", true},
+		{"latin-1", []byte("caf\xE9 cr\xE8me"), "unknown", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := extract(t, extractor.FormatText, tc.data)
+			if result.Encoding != tc.encoding {
+				t.Errorf("Expected encoding %s, got %s", tc.encoding, result.Encoding)
+			}
+			if (len(result.Warnings) > 0) != tc.warns {
+				t.Errorf("Unexpected warnings: %v", result.Warnings)
+			}
+		})
+	}
+}
+
+func TestExtractWarnsOnEmptyText(t *testing.T) {
+	result := extract(t, extractor.FormatText, []byte("  \n"))
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "rejected") {
+		t.Errorf("Expected a rejection warning, got %v", result.Warnings)
+	}
+}
+
+func TestExtractRejectsUnknownFormat(t *testing.T) {
+	if extractor.FormatOf("Scan.PDF") != extractor.FormatPDF {
+		t.Errorf("Expected the format to ignore case")
+	}
+	_, err := extractor.Extract("png", bytes.NewReader(nil), 0, nil)
+	if !errors.Is(err, extractor.ErrUnsupportedFormat) {
+		t.Errorf("Expected ErrUnsupportedFormat, got %v", err)
+	}
+}
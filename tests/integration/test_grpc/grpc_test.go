@@ -0,0 +1,100 @@
+package test_grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/zjoart/docai/internal/auth"
+	"github.com/zjoart/docai/internal/documents"
+	"github.com/zjoart/docai/internal/grpcserver"
+	docaiv1 "github.com/zjoart/docai/pkg/api/docai/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bootstrapKey = "test-bootstrap-key"
+
+// startServer serves the document API over an in-memory connection. Only
+// the bootstrap key authenticates and there is no database, so calls must
+// fail before they reach the service.
+func startServer(t *testing.T) *grpc.ClientConn {
+	authService, err := auth.NewService(nil, nil, auth.Config{BootstrapKey: bootstrapKey})
+	if err != nil {
+		t.Fatalf("Failed to create auth service: %v", err)
+	}
+
+	srv := grpcserver.NewServer(authService, nil)
+	docaiv1.RegisterDocumentServiceServer(srv, documents.NewGRPCServer(nil, nil))
+	healthpb.RegisterHealthServer(srv, grpchealth.NewServer())
+
+	lis := bufconn.Listen(1 << 20)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestCallsRequireCredentials(t *testing.T) {
+	client := docaiv1.NewDocumentServiceClient(startServer(t))
+
+	_, err := client.GetDocument(context.Background(), &docaiv1.GetDocumentRequest{Id: "not-a-uuid"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated without credentials, got %v", err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")
+	_, err = client.GetDocument(ctx, &docaiv1.GetDocumentRequest{Id: "not-a-uuid"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for a bad credential, got %v", err)
+	}
+
+	// streams go through the same checks
+	stream, err := client.WatchEvents(context.Background(), &docaiv1.WatchEventsRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Expected Unauthenticated for a stream, got %v", err)
+	}
+}
+
+func TestAuthenticatedCallsReachTheService(t *testing.T) {
+	client := docaiv1.NewDocumentServiceClient(startServer(t))
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", bootstrapKey, grpcserver.RequestIDKey, "req-123")
+
+	var header metadata.MD
+	_, err := client.GetDocument(ctx, &docaiv1.GetDocumentRequest{Id: "not-a-uuid"}, grpc.Header(&header))
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Expected InvalidArgument for a bad ID, got %v", err)
+	}
+	if got := header.Get(grpcserver.RequestIDKey); len(got) != 1 || got[0] != "req-123" {
+		t.Errorf("Expected the request ID to be echoed, got %v", got)
+	}
+
+	_, err = client.ListDocuments(ctx, &docaiv1.ListDocumentsRequest{Limit: 1000})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument for a limit over the maximum, got %v", err)
+	}
+}
+
+func TestHealthNeedsNoCredentials(t *testing.T) {
+	resp, err := healthpb.NewHealthClient(startServer(t)).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("Expected SERVING, got %v, %v", resp, err)
+	}
+}
@@ -0,0 +1,88 @@
+package test_health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/health"
+)
+
+func serve(t *testing.T, probes *health.Service, path string) (int, health.Report) {
+	r := mux.NewRouter()
+	health.RegisterRoutes(r, probes)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+	var report health.Report
+	json.Unmarshal(w.Body.Bytes(), &report)
+	return w.Code, report
+}
+
+func ok(context.Context) error { return nil }
+
+func TestReadyWhenAllChecksPass(t *testing.T) {
+	probes := health.NewService(time.Second)
+	probes.Register("database", ok)
+	probes.Register("storage", ok)
+
+	code, report := serve(t, probes, "/readyz")
+	if code != http.StatusOK || report.Status != health.StatusOK {
+		t.Fatalf("Expected ready, got %d %+v", code, report)
+	}
+	if len(report.Checks) != 2 || report.Checks["storage"].Status != health.StatusOK {
+		t.Errorf("Expected per-dependency results, got %+v", report.Checks)
+	}
+}
+
+func TestNotReadyWhenADependencyFails(t *testing.T) {
+	probes := health.NewService(time.Second)
+	probes.Register("database", ok)
+	probes.Register("storage", func(context.Context) error { return errors.New("bucket \"docs\" does not exist") })
+
+	code, report := serve(t, probes, "/readyz")
+	if code != http.StatusServiceUnavailable || report.Status != health.StatusUnavailable {
+		t.Fatalf("Expected 503, got %d %+v", code, report)
+	}
+	if report.Checks["storage"].Error == "" || report.Checks["database"].Status != health.StatusOK {
+		t.Errorf("Unexpected check results: %+v", report.Checks)
+	}
+
+	if code, _ := serve(t, probes, "/healthz"); code != http.StatusOK {
+		t.Errorf("Liveness must not depend on dependencies, got %d", code)
+	}
+}
+
+func TestChecksAreBoundedByTimeout(t *testing.T) {
+	probes := health.NewService(50 * time.Millisecond)
+	probes.Register("llm", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	started := time.Now()
+	code, _ := serve(t, probes, "/readyz")
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Expected a hung check to fail readiness, got %d", code)
+	}
+	if time.Since(started) > time.Second {
+		t.Errorf("Readiness took %s despite the timeout", time.Since(started))
+	}
+}
+
+func TestNotReadyWhileDraining(t *testing.T) {
+	probes := health.NewService(time.Second)
+	probes.Register("database", ok)
+	probes.Drain()
+
+	code, report := serve(t, probes, "/readyz")
+	if code != http.StatusServiceUnavailable || report.Status != health.StatusDraining {
+		t.Fatalf("Expected draining 503, got %d %+v", code, report)
+	}
+}
@@ -0,0 +1,39 @@
+package test_httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/zjoart/docai/internal/httputil"
+)
+
+func TestStatusRecorderKeepsTheFirstStatus(t *testing.T) {
+	w := httptest.NewRecorder()
+	rec := httputil.NewStatusRecorder(w)
+
+	rec.WriteHeader(http.StatusAccepted)
+	rec.WriteHeader(http.StatusInternalServerError) // superfluous, ignored by net/http too
+	rec.Write([]byte("hello"))
+	rec.Flush()
+
+	if rec.Status() != http.StatusAccepted || rec.Bytes() != 5 {
+		t.Errorf("Expected 202 and 5 bytes, got %d and %d", rec.Status(), rec.Bytes())
+	}
+	if !w.Flushed || w.Body.String() != "hello" {
+		t.Errorf("Expected the body and flush to pass through, got %q flushed=%v", w.Body.String(), w.Flushed)
+	}
+	if rec.Unwrap() != w {
+		t.Errorf("Expected Unwrap to return the underlying writer")
+	}
+}
+
+func TestStatusRecorderDefaultsToOK(t *testing.T) {
+	rec := httputil.NewStatusRecorder(httptest.NewRecorder())
+	rec.Write([]byte("body"))
+	rec.WriteHeader(http.StatusNotFound) // too late to change the status
+
+	if rec.Status() != http.StatusOK {
+		t.Errorf("Expected 200 for a body written without a status, got %d", rec.Status())
+	}
+}
@@ -0,0 +1,260 @@
+package test_idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/idempotency"
+	"github.com/zjoart/docai/internal/tenant"
+)
+
+// memoryRepository keeps records in a map, standing in for Postgres.
+type memoryRepository struct {
+	mu      sync.Mutex
+	records map[string]idempotency.Record
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{records: map[string]idempotency.Record{}}
+}
+
+func (m *memoryRepository) Reserve(ctx context.Context, rec *idempotency.Record, staleBefore time.Time) (*idempotency.Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id := rec.TenantID + "/" + rec.Key
+	existing, ok := m.records[id]
+	stale := !existing.Completed() && existing.LockedAt.Before(staleBefore)
+	if ok && existing.ExpiresAt.After(rec.CreatedAt) && !stale {
+		return &existing, nil
+	}
+	m.records[id] = *rec
+	return nil, nil
+}
+
+func (m *memoryRepository) Refresh(ctx context.Context, tenantID, key string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if rec, ok := m.records[tenantID+"/"+key]; ok && !rec.Completed() {
+		rec.LockedAt = at
+		m.records[tenantID+"/"+key] = rec
+	}
+	return nil
+}
+
+func (m *memoryRepository) Complete(ctx context.Context, rec *idempotency.Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[rec.TenantID+"/"+rec.Key] = *rec
+	return nil
+}
+
+func (m *memoryRepository) Release(ctx context.Context, tenantID, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, tenantID+"/"+key)
+	return nil
+}
+
+func (m *memoryRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *memoryRepository) ListDataKeys(ctx context.Context, after idempotency.DataKeyRef, limit int) ([]idempotency.DataKeyRef, error) {
+	return nil, nil
+}
+
+func (m *memoryRepository) UpdateDataKey(ctx context.Context, ref idempotency.DataKeyRef, wrapped string) error {
+	return nil
+}
+
+func multipartUpload(t *testing.T, key, filename, content string) *http.Request {
+	t.Helper()
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body) // a new random boundary every time
+	part, _ := writer.CreateFormFile("file", filename)
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/documents/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set(idempotency.Header, key)
+	return req.WithContext(tenant.WithScope(req.Context(), tenant.Scope{TenantID: "acme"}))
+}
+
+func noLimit(*http.Request) int64 { return 1 << 20 }
+
+// countingUpload answers with the uploaded file's content and how many
+// times it has run.
+func countingUpload(calls *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "no file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]interface{}{"filename": header.Filename, "run": *calls})
+	}
+}
+
+func problemCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Code string `json:"code"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	return body.Code
+}
+
+func TestRetryReplaysTheFirstResponse(t *testing.T) {
+	key := make([]byte, 32)
+	rand.Read(key)
+	keys, err := envelope.NewKeyring("test", map[string][]byte{"test": key})
+	if err != nil {
+		t.Fatalf("Failed to create keyring: %v", err)
+	}
+	repo := newMemoryRepository()
+	var calls int
+	h := idempotency.NewService(repo, keys, idempotency.Config{}).Handle(noLimit, countingUpload(&calls))
+
+	first := httptest.NewRecorder()
+	h(first, multipartUpload(t, "key-1", "note.txt", "hello"))
+	retry := httptest.NewRecorder()
+	h(retry, multipartUpload(t, "key-1", "note.txt", "hello"))
+
+	if calls != 1 {
+		t.Fatalf("Expected the handler to run once, ran %d times", calls)
+	}
+	if retry.Code != http.StatusOK || retry.Body.String() != first.Body.String() {
+		t.Errorf("Expected the first response to be replayed, got %d %q", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get(idempotency.ReplayedHeader) != "true" || retry.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected replay headers: %v", retry.Header())
+	}
+	if stored := repo.records["acme/key-1"]; !envelope.IsSealed(stored.ResponseBody) {
+		t.Errorf("Expected the stored response to be encrypted, got %q", stored.ResponseBody)
+	}
+
+	// without a key every request runs
+	req := multipartUpload(t, "", "note.txt", "hello")
+	h(httptest.NewRecorder(), req)
+	if calls != 2 {
+		t.Errorf("Expected a request without a key to run, ran %d times", calls)
+	}
+}
+
+func TestKeyReuseForADifferentRequestIsRejected(t *testing.T) {
+	var calls int
+	h := idempotency.NewService(newMemoryRepository(), nil, idempotency.Config{}).Handle(noLimit, countingUpload(&calls))
+
+	h(httptest.NewRecorder(), multipartUpload(t, "key-1", "note.txt", "hello"))
+	w := httptest.NewRecorder()
+	h(w, multipartUpload(t, "key-1", "note.txt", "something else"))
+
+	if w.Code != http.StatusUnprocessableEntity || problemCode(t, w) != "idempotency_key_reused" {
+		t.Errorf("Expected 422 idempotency_key_reused, got %d", w.Code)
+	}
+	if calls != 1 {
+		t.Errorf("Expected the second request not to run, ran %d times", calls)
+	}
+}
+
+func TestRequestInProgressIsConflict(t *testing.T) {
+	svc := idempotency.NewService(newMemoryRepository(), nil, idempotency.Config{})
+
+	var inner *httptest.ResponseRecorder
+	h := svc.Handle(noLimit, func(w http.ResponseWriter, r *http.Request) {
+		// a retry arrives while the first request is still running
+		inner = httptest.NewRecorder()
+		svc.Handle(noLimit, countingUpload(new(int)))(inner, multipartUpload(t, "key-1", "note.txt", "hello"))
+		w.WriteHeader(http.StatusOK)
+	})
+	h(httptest.NewRecorder(), multipartUpload(t, "key-1", "note.txt", "hello"))
+
+	if inner.Code != http.StatusConflict || inner.Header().Get("Retry-After") == "" || problemCode(t, inner) != "idempotency_key_in_use" {
+		t.Errorf("Expected 409 idempotency_key_in_use with Retry-After, got %d %v", inner.Code, inner.Header())
+	}
+}
+
+func TestSlowRequestKeepsItsKey(t *testing.T) {
+	svc := idempotency.NewService(newMemoryRepository(), nil, idempotency.Config{LockTimeout: 30 * time.Millisecond})
+
+	var calls int
+	var retry *httptest.ResponseRecorder
+	h := svc.Handle(noLimit, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls > 1 {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		// a slow LLM call, well past the lock timeout, before the retry
+		time.Sleep(150 * time.Millisecond)
+		retry = httptest.NewRecorder()
+		svc.Handle(noLimit, countingUpload(&calls))(retry, multipartUpload(t, "key-1", "note.txt", "hello"))
+		w.WriteHeader(http.StatusOK)
+	})
+	h(httptest.NewRecorder(), multipartUpload(t, "key-1", "note.txt", "hello"))
+
+	if calls != 1 || retry.Code != http.StatusConflict {
+		t.Errorf("Expected the retry to wait for the running request, got %d calls and %d", calls, retry.Code)
+	}
+}
+
+func TestServerErrorsAreNotStored(t *testing.T) {
+	var calls int
+	h := idempotency.NewService(newMemoryRepository(), nil, idempotency.Config{}).Handle(noLimit, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	h(httptest.NewRecorder(), multipartUpload(t, "key-1", "note.txt", "hello"))
+	w := httptest.NewRecorder()
+	h(w, multipartUpload(t, "key-1", "note.txt", "hello"))
+
+	if calls != 2 || w.Code != http.StatusOK {
+		t.Errorf("Expected the retry after a 500 to run again, got %d calls and %d", calls, w.Code)
+	}
+}
+
+func TestJSONBodiesAreCompared(t *testing.T) {
+	var calls int
+	h := idempotency.NewService(newMemoryRepository(), nil, idempotency.Config{}).Handle(noLimit, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusAccepted)
+	})
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/documents/analyze", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotency.Header, "batch-1")
+		req = req.WithContext(tenant.WithScope(req.Context(), tenant.Scope{TenantID: "acme"}))
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w
+	}
+
+	send(`{"ids": ["a", "b"], "filter": null}`)
+	// the same request serialised differently is still a retry
+	if w := send(`{"filter":null,"ids":["a","b"]}`); w.Code != http.StatusAccepted || calls != 1 {
+		t.Errorf("Expected a replay, got %d after %d calls", w.Code, calls)
+	}
+	if w := send(`{"ids": ["a"]}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a different body, got %d", w.Code)
+	}
+}
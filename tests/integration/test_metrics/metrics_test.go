@@ -0,0 +1,81 @@
+package test_metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/documents"
+	"github.com/zjoart/docai/internal/documents/analyzer"
+	"github.com/zjoart/docai/internal/metrics"
+)
+
+// The pipeline only sees its own interfaces.
+var (
+	_ documents.Metrics = (*metrics.Metrics)(nil)
+	_ analyzer.Observer = (*metrics.Metrics)(nil)
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Scrape failed: status %d", w.Code)
+	}
+	body, _ := io.ReadAll(w.Body)
+	return string(body)
+}
+
+func TestPipelineMetricsAreExported(t *testing.T) {
+	m := metrics.New()
+	m.ObserveUpload("pdf", 4096)
+	m.ObserveExtraction("pdf", 20*time.Millisecond, nil)
+	m.ObserveExtraction("docx", 5*time.Millisecond, errors.New("corrupt archive"))
+	m.ObserveLLMRequest("gpt-4o-mini", 2*time.Second, 1200, nil)
+	m.ObserveLLMRequest("gpt-4o-mini", time.Second, 0, errors.New("timeout"))
+	m.RegisterQueueDepth(func() int64 { return 7 })
+	m.RegisterStatusCounts(func(ctx context.Context) (map[string]int64, error) {
+		return map[string]int64{"analyzed": 3, "failed": 1}, nil
+	})
+
+	out := scrape(t, m)
+	for _, want := range []string{
+		`docai_upload_size_bytes_count{format="pdf"} 1`,
+		`docai_extraction_duration_seconds_count{format="pdf"} 1`,
+		`docai_extraction_failures_total{format="docx"} 1`,
+		`docai_llm_request_duration_seconds_count{model="gpt-4o-mini"} 2`,
+		`docai_llm_tokens_total{model="gpt-4o-mini"} 1200`,
+		`docai_llm_errors_total{model="gpt-4o-mini"} 1`,
+		`docai_analysis_queue_depth 7`,
+		`docai_documents{status="analyzed"} 3`,
+		`docai_documents{status="failed"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Missing %q in scrape output", want)
+		}
+	}
+}
+
+func TestHTTPMetricsUseRouteTemplates(t *testing.T) {
+	m := metrics.New()
+	r := mux.NewRouter()
+	r.Use(m.Middleware)
+	r.HandleFunc("/documents/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+
+	for _, id := range []string{"a", "b"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/documents/"+id, nil))
+	}
+
+	want := `docai_http_request_duration_seconds_count{method="GET",route="/documents/{id}",status="404"} 2`
+	if out := scrape(t, m); !strings.Contains(out, want) {
+		t.Errorf("Missing %q in scrape output", want)
+	}
+}
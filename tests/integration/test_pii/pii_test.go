@@ -0,0 +1,86 @@
+package test_pii
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zjoart/docai/internal/pii"
+)
+
+const sample = `Jane Doe, jane.doe@example.com, +44 20 7946 0958.
+IBAN: GB82 WEST 1234 5698 7654 32. Card 4111 1111 1111 1111, SSN 123-45-6789.
+Invoice date 2024-01-15, order 4111 1111 1111 1112, contact jane.doe@example.com.`
+
+func TestDetect(t *testing.T) {
+	found := map[pii.Kind][]string{}
+	for _, m := range pii.Detect(sample, nil) {
+		found[m.Kind] = append(found[m.Kind], m.Value)
+	}
+
+	expected := map[pii.Kind]string{
+		pii.KindEmail:      "jane.doe@example.com",
+		pii.KindPhone:      "+44 20 7946 0958",
+		pii.KindIBAN:       "GB82 WEST 1234 5698 7654 32",
+		pii.KindCard:       "4111 1111 1111 1111",
+		pii.KindNationalID: "123-45-6789",
+	}
+	for kind, value := range expected {
+		if len(found[kind]) == 0 || found[kind][0] != value {
+			t.Errorf("Expected %s %q, got %v", kind, value, found[kind])
+		}
+	}
+
+	for _, values := range found {
+		for _, v := range values {
+			if v == "4111 1111 1111 1112" || v == "2024-01-15" {
+				t.Errorf("Detected %q, which fails validation", v)
+			}
+		}
+	}
+}
+
+func TestRedactAndRestore(t *testing.T) {
+	r := pii.Apply(sample, pii.Policy{Mode: pii.ModeRedact})
+
+	for _, leaked := range []string{"jane.doe@example.com", "GB82 WEST", "4111 1111 1111 1111", "123-45-6789"} {
+		if strings.Contains(r.Text, leaked) {
+			t.Errorf("Redacted text still contains %q", leaked)
+		}
+	}
+	if strings.Count(r.Text, "[EMAIL_1]") != 2 {
+		t.Errorf("Expected the repeated email to share one token, got: %s", r.Text)
+	}
+
+	metadata := map[string]interface{}{
+		"email":   "[EMAIL_1]",
+		"payment": map[string]interface{}{"iban": "[IBAN_1]"},
+	}
+	r.RestoreValue(metadata)
+	if metadata["email"] != "jane.doe@example.com" {
+		t.Errorf("Expected email restored, got %v", metadata["email"])
+	}
+	if iban := metadata["payment"].(map[string]interface{})["iban"]; iban != "GB82 WEST 1234 5698 7654 32" {
+		t.Errorf("Expected IBAN restored, got %v", iban)
+	}
+}
+
+func TestPolicyModes(t *testing.T) {
+	strict := pii.Apply(sample, pii.Policy{Mode: pii.ModeStrict})
+	if got := strict.Restore("[EMAIL_1]"); got != "[EMAIL_1]" {
+		t.Errorf("Strict mode should keep placeholders, got %q", got)
+	}
+
+	off := pii.Apply(sample, pii.Policy{Mode: pii.ModeOff})
+	if off.Text != sample {
+		t.Error("Off mode should not change the text")
+	}
+
+	emailOnly := pii.Apply(sample, pii.Policy{Mode: pii.ModeRedact, Kinds: []pii.Kind{pii.KindEmail}})
+	if !strings.Contains(emailOnly.Text, "123-45-6789") || strings.Contains(emailOnly.Text, "jane.doe@example.com") {
+		t.Errorf("Expected only emails redacted, got: %s", emailOnly.Text)
+	}
+
+	if err := (pii.Policy{Mode: "loose"}).Validate(); err == nil {
+		t.Error("Expected invalid mode to be rejected")
+	}
+}
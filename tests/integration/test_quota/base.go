@@ -0,0 +1,59 @@
+package test_quota
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+	"github.com/zjoart/docai/internal/config"
+	"github.com/zjoart/docai/internal/database"
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/tenant"
+)
+
+type TestEnv struct {
+	Router   http.Handler
+	Quotas   *quota.Service
+	TenantID string
+}
+
+// SetupTestEnv creates a fresh tenant with the given limits and a router
+// that rate limits a /probe route for it.
+func SetupTestEnv(t *testing.T, limits tenant.Limits) *TestEnv {
+
+	_ = godotenv.Load("../../../.env")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.Connect(cfg.DBURL)
+	if err != nil {
+		t.Fatalf("DB connect failed: %v", err)
+	}
+
+	tenants := tenant.NewRepository(db)
+	tenantID := fmt.Sprintf("quota-%s", uuid.New().String()[:8])
+	if err := tenants.Create(&tenant.Tenant{ID: tenantID, Name: tenantID, Limits: limits}); err != nil {
+		t.Fatalf("Tenant create failed: %v", err)
+	}
+
+	quotas := quota.NewService(quota.NewRepository(db), tenants, quota.Limits{})
+
+	r := mux.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(tenant.WithScope(r.Context(), tenant.Scope{TenantID: tenantID})))
+		})
+	})
+	r.Use(quotas.Middleware)
+	r.HandleFunc("/probe", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("GET")
+
+	return &TestEnv{Router: r, Quotas: quotas, TenantID: tenantID}
+}
@@ -0,0 +1,61 @@
+package test_quota
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/tenant"
+)
+
+func TestRequestRateLimit(t *testing.T) {
+	rpm := 2
+	env := SetupTestEnv(t, tenant.Limits{RequestsPerMinute: &rpm})
+
+	for i := 0; i < rpm; i++ {
+		w := httptest.NewRecorder()
+		env.Router.ServeHTTP(w, httptest.NewRequest("GET", "/probe", nil))
+		if w.Code != http.StatusNoContent {
+			t.Fatalf("Request %d: expected 204, got %d", i+1, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	env.Router.ServeHTTP(w, httptest.NewRequest("GET", "/probe", nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected 429 once the limit is spent, got %d", w.Code)
+	}
+
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 || retryAfter > 60 {
+		t.Errorf("Expected Retry-After between 1 and 60 seconds, got %q", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestDailyAnalysisQuota(t *testing.T) {
+	var daily int64 = 1
+	env := SetupTestEnv(t, tenant.Limits{DailyAnalyses: &daily})
+	ctx := context.Background()
+
+	if err := env.Quotas.ReserveAnalysis(ctx, env.TenantID); err != nil {
+		t.Fatalf("First analysis should fit the quota: %v", err)
+	}
+
+	err := env.Quotas.ReserveAnalysis(ctx, env.TenantID)
+	exceeded, ok := quota.AsExceeded(err)
+	if !ok {
+		t.Fatalf("Expected ExceededError, got %v", err)
+	}
+	if exceeded.Limit != quota.LimitAnalyses {
+		t.Errorf("Expected limit %q, got %q", quota.LimitAnalyses, exceeded.Limit)
+	}
+
+	// releasing the reservation frees the slot again
+	env.Quotas.ReleaseAnalysis(ctx, env.TenantID)
+	if err := env.Quotas.ReserveAnalysis(ctx, env.TenantID); err != nil {
+		t.Errorf("Expected released slot to be reusable: %v", err)
+	}
+}
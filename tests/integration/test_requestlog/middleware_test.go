@@ -0,0 +1,72 @@
+package test_requestlog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/requestlog"
+	"github.com/zjoart/docai/pkg/logger"
+)
+
+func TestRequestIDIsPropagated(t *testing.T) {
+	var seen string
+	h := requestlog.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestIDFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(requestlog.RequestIDHeader, "client-supplied")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if seen != "client-supplied" {
+		t.Errorf("Expected handler to see the client's request ID, got %q", seen)
+	}
+	if got := w.Header().Get(requestlog.RequestIDHeader); got != "client-supplied" {
+		t.Errorf("Expected request ID to be echoed, got %q", got)
+	}
+	if w.Code != http.StatusNoContent {
+		t.Errorf("Expected status to pass through, got %d", w.Code)
+	}
+}
+
+func TestRequestIDIsGenerated(t *testing.T) {
+	var seen string
+	h := requestlog.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestIDFromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(requestlog.RequestIDHeader, strings.Repeat("x", 500))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if seen == "" || len(seen) > 128 {
+		t.Fatalf("Expected a generated request ID, got %q", seen)
+	}
+	if got := w.Header().Get(requestlog.RequestIDHeader); got != seen {
+		t.Errorf("Expected generated ID %q in response, got %q", seen, got)
+	}
+}
+
+func TestDetachKeepsLoggingIDs(t *testing.T) {
+	ctx := logger.WithDocumentID(logger.WithRequestID(context.Background(), "req-1"), "doc-1")
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+
+	detached := audit.Detach(cancelled)
+	if detached.Err() != nil {
+		t.Fatalf("Detached context should not be cancelled")
+	}
+	if got := logger.RequestIDFromContext(detached); got != "req-1" {
+		t.Errorf("Expected request ID to survive Detach, got %q", got)
+	}
+	if got := logger.DocumentIDFromContext(detached); got != "doc-1" {
+		t.Errorf("Expected document ID to survive Detach, got %q", got)
+	}
+}
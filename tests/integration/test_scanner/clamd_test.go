@@ -0,0 +1,129 @@
+package test_scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zjoart/docai/internal/scanner"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd speaks enough of the INSTREAM protocol to reassemble the stream
+// and reports FOUND when it contains the EICAR test string.
+func fakeClamd(t *testing.T) (string, <-chan []byte) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []byte, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				cmd, err := r.ReadString(0)
+				if err != nil || cmd != "zINSTREAM\x00" {
+					conn.Write([]byte("UNKNOWN COMMAND\x00"))
+					return
+				}
+
+				var data []byte
+				size := make([]byte, 4)
+				for {
+					if _, err := io.ReadFull(r, size); err != nil {
+						return
+					}
+					n := binary.BigEndian.Uint32(size)
+					if n == 0 {
+						break
+					}
+					chunk := make([]byte, n)
+					if _, err := io.ReadFull(r, chunk); err != nil {
+						return
+					}
+					data = append(data, chunk...)
+				}
+				received <- data
+
+				if bytes.Contains(data, []byte(eicar)) {
+					conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+					return
+				}
+				conn.Write([]byte("stream: OK\x00"))
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String(), received
+}
+
+func TestClamdCleanFile(t *testing.T) {
+	addr, received := fakeClamd(t)
+	clamd := scanner.NewClamd(addr, 5*time.Second)
+
+	result, err := clamd.Scan(context.Background(), strings.NewReader("quarterly report"))
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if result.Infected {
+		t.Fatalf("Expected clean result, got %+v", result)
+	}
+	if got := <-received; string(got) != "quarterly report" {
+		t.Fatalf("clamd received %q", got)
+	}
+}
+
+func TestClamdInfectedFile(t *testing.T) {
+	addr, _ := fakeClamd(t)
+	clamd := scanner.NewClamd(addr, 5*time.Second)
+
+	result, err := clamd.Scan(context.Background(), strings.NewReader(eicar))
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if !result.Infected || result.Signature != "Eicar-Test-Signature" {
+		t.Fatalf("Expected EICAR detection, got %+v", result)
+	}
+}
+
+func TestClamdStreamsLargeFilesInChunks(t *testing.T) {
+	addr, received := fakeClamd(t)
+	clamd := scanner.NewClamd(addr, 5*time.Second)
+
+	payload := bytes.Repeat([]byte("a"), 200*1024)
+	if _, err := clamd.Scan(context.Background(), bytes.NewReader(payload)); err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if got := <-received; !bytes.Equal(got, payload) {
+		t.Fatalf("clamd received %d bytes, want %d", len(got), len(payload))
+	}
+}
+
+func TestClamdUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	clamd := scanner.NewClamd(addr, time.Second)
+	if _, err := clamd.Scan(context.Background(), strings.NewReader("x")); !errors.Is(err, scanner.ErrUnavailable) {
+		t.Fatalf("Expected ErrUnavailable, got %v", err)
+	}
+}
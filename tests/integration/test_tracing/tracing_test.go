@@ -0,0 +1,74 @@
+package test_tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestServerSpanIsNamedAfterRoute(t *testing.T) {
+	recorder := recordSpans(t)
+
+	r := mux.NewRouter()
+	r.Use(tracing.RouteMiddleware)
+	r.HandleFunc("/documents/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracing.Start(r.Context(), "documents.GetDocument")
+		span.End()
+	}).Methods("GET")
+
+	tracing.Handler(r).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/documents/42", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	inner, server := spans[0], spans[1]
+	if server.Name() != "GET /documents/{id}" {
+		t.Errorf("Expected server span to be named after the route, got %q", server.Name())
+	}
+	if inner.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Errorf("Expected handler span to be a child of the server span")
+	}
+}
+
+func TestDetachedWorkStaysInTrace(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx, parent := tracing.Start(ctx, "documents.UploadDocument")
+	detached := audit.Detach(ctx)
+	cancel()
+	parent.End()
+
+	_, child := tracing.Start(detached, "documents.AnalyzeDocument")
+	child.End()
+
+	if detached.Err() != nil {
+		t.Fatalf("Detached context should outlive the request")
+	}
+	spans := recorder.Ended()
+	if got := spans[1].SpanContext().TraceID(); got != parent.SpanContext().TraceID() {
+		t.Errorf("Expected background span in trace %s, got %s", parent.SpanContext().TraceID(), got)
+	}
+	if !trace.SpanContextFromContext(detached).IsValid() {
+		t.Errorf("Expected detached context to carry the span context")
+	}
+}
@@ -0,0 +1,115 @@
+package test_webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/joho/godotenv"
+	"github.com/zjoart/docai/internal/audit"
+	"github.com/zjoart/docai/internal/auth"
+	"github.com/zjoart/docai/internal/config"
+	"github.com/zjoart/docai/internal/database"
+	"github.com/zjoart/docai/internal/documents"
+	"github.com/zjoart/docai/internal/documents/analyzer"
+	"github.com/zjoart/docai/internal/envelope"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/pii"
+	"github.com/zjoart/docai/internal/quota"
+	"github.com/zjoart/docai/internal/storage"
+	"github.com/zjoart/docai/internal/tenant"
+	"github.com/zjoart/docai/internal/webhooks"
+)
+
+type TestEnv struct {
+	Router *mux.Router
+}
+
+func SetupTestEnv(t *testing.T) *TestEnv {
+
+	_ = godotenv.Load("../../../.env")
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.Connect(cfg.DBURL)
+	if err != nil {
+		t.Fatalf("DB connect failed: %v", err)
+	}
+
+	minioClient, err := storage.NewMinioClient(cfg.MinioEndpoint, cfg.MinioAccessKey, cfg.MinioSecretKey, cfg.MinioBucket)
+	if err != nil {
+		t.Fatalf("Minio init failed: %v", err)
+	}
+
+	keys := newTestKeyring(t)
+	bus := events.NewBus()
+	repo := documents.NewRepository(db, keys)
+	svc := documents.NewService(repo, minioClient, analyzer.NewAnalyzer(cfg.OpenRouterAPIKey, cfg.LLMModel, nil), bus, quota.NewService(quota.NewRepository(db), tenant.NewRepository(db), quota.Limits{}), pii.NewRedactor(tenant.NewRepository(db), pii.Policy{Mode: pii.ModeRedact}), keys, nil, audit.NewService(audit.NewRepository(db)), nil, nil)
+	batches := documents.NewBatchRunner(svc, repo, documents.BatchConfig{Workers: 1})
+
+	webhookRepo := webhooks.NewRepository(db)
+	dispatcher := webhooks.NewDispatcher(webhookRepo, bus, webhooks.Config{
+		MaxAttempts:  3,
+		BaseBackoff:  100 * time.Millisecond,
+		PollInterval: 100 * time.Millisecond,
+		// the test receiver listens on loopback
+		AllowPrivateNetworks: true,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go dispatcher.Run(ctx)
+
+	r := mux.NewRouter()
+	r.Use(withTestPrincipal)
+	documents.RegisterRoutes(r, documents.NewHandler(svc, batches, documents.HandlerConfig{}))
+	webhooks.RegisterRoutes(r, webhooks.NewHandler(webhooks.NewService(webhookRepo, dispatcher)))
+
+	return &TestEnv{Router: r}
+}
+
+// withTestPrincipal authenticates every request as an admin of the default
+// tenant so tests can exercise routes without provisioning API keys. The
+// X-Test-Tenant header switches the tenant.
+func withTestPrincipal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := &auth.Principal{
+			Subject:  "integration-test",
+			Method:   auth.MethodBootstrap,
+			TenantID: tenant.DefaultTenant,
+			Scopes:   auth.ScopeList{auth.ScopeAdmin},
+		}
+		if t := r.Header.Get("X-Test-Tenant"); t != "" {
+			principal.TenantID = t
+		}
+
+		scope, err := principal.TenantScope()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		ctx := auth.WithPrincipal(r.Context(), principal)
+		ctx = tenant.WithScope(ctx, scope)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// newTestKeyring returns a keyring with a random master key so uploads are
+// encrypted just as in production.
+func newTestKeyring(t *testing.T) *envelope.Keyring {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Master key generation failed: %v", err)
+	}
+	keys, err := envelope.NewKeyring("test", map[string][]byte{"test": key})
+	if err != nil {
+		t.Fatalf("Keyring init failed: %v", err)
+	}
+	return keys
+}
@@ -0,0 +1,92 @@
+package test_webhooks
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/events"
+	"github.com/zjoart/docai/internal/webhooks"
+)
+
+// oneDeliveryRepository hands out a single due delivery to sub and reports
+// the recorded outcome, standing in for Postgres.
+type oneDeliveryRepository struct {
+	webhooks.Repository
+	sub     webhooks.Subscription
+	once    sync.Once
+	updated chan webhooks.Delivery
+}
+
+func (r *oneDeliveryRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]webhooks.Delivery, error) {
+	var due []webhooks.Delivery
+	r.once.Do(func() {
+		due = []webhooks.Delivery{{ID: uuid.New(), SubscriptionID: r.sub.ID, EventType: string(events.DocumentAnalyzed), Payload: []byte(`{}`)}}
+	})
+	return due, nil
+}
+
+func (r *oneDeliveryRepository) FindSubscriptionByID(ctx context.Context, id uuid.UUID) (*webhooks.Subscription, error) {
+	return &r.sub, nil
+}
+
+func (r *oneDeliveryRepository) UpdateDelivery(ctx context.Context, delivery *webhooks.Delivery) error {
+	r.updated <- *delivery
+	return nil
+}
+
+func deliverOnce(t *testing.T, url string, allowPrivate bool) webhooks.Delivery {
+	t.Helper()
+	repo := &oneDeliveryRepository{
+		sub:     webhooks.Subscription{ID: uuid.New(), URL: url, Secret: "secret", Active: true},
+		updated: make(chan webhooks.Delivery, 1),
+	}
+	dispatcher := webhooks.NewDispatcher(repo, events.NewBus(), webhooks.Config{MaxAttempts: 1, AllowPrivateNetworks: allowPrivate})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go dispatcher.Run(ctx)
+	dispatcher.Wake()
+
+	select {
+	case delivery := <-repo.updated:
+		return delivery
+	case <-time.After(5 * time.Second):
+		t.Fatal("Delivery was not attempted")
+		return webhooks.Delivery{}
+	}
+}
+
+func TestPrivateEndpointsAreRefused(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	delivery := deliverOnce(t, srv.URL, false)
+	if hits.Load() != 0 || !strings.Contains(delivery.LastError, webhooks.ErrPrivateAddress.Error()) {
+		t.Errorf("Expected the loopback endpoint to be refused, got %d hits and %q", hits.Load(), delivery.LastError)
+	}
+	if delivery.Status != webhooks.DeliveryFailed {
+		t.Errorf("Expected a failed delivery, got %q", delivery.Status)
+	}
+}
+
+func TestErrorResponseBodiesAreNotStored(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"AccessKeyId": "secret"}`, http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	delivery := deliverOnce(t, srv.URL, true)
+	if delivery.LastError != "endpoint returned 500" || delivery.ResponseStatus != http.StatusInternalServerError {
+		t.Errorf("Expected only the status to be recorded, got %d %q", delivery.ResponseStatus, delivery.LastError)
+	}
+}
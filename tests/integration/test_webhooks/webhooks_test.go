@@ -0,0 +1,121 @@
+package test_webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/zjoart/docai/internal/webhooks"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+
+	env := SetupTestEnv(t)
+	r := env.Router
+
+	received := make(chan webhooks.Payload, 4)
+	var secret string
+
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		var timestamp int64
+		var signature string
+		for _, part := range strings.Split(req.Header.Get(webhooks.SignatureHeader), ",") {
+			if v, ok := strings.CutPrefix(part, "t="); ok {
+				timestamp, _ = strconv.ParseInt(v, 10, 64)
+			}
+			if v, ok := strings.CutPrefix(part, "v1="); ok {
+				signature = v
+			}
+		}
+
+		if !hmac.Equal([]byte(signature), []byte(webhooks.Sign(secret, timestamp, body))) {
+			t.Errorf("Invalid webhook signature")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var payload webhooks.Payload
+		json.Unmarshal(body, &payload)
+		received <- payload
+	}))
+	defer receiver.Close()
+
+	subBody, _ := json.Marshal(webhooks.SubscriptionInput{URL: receiver.URL, Events: []string{"document.uploaded"}})
+	subReq := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(subBody))
+	subW := httptest.NewRecorder()
+	r.ServeHTTP(subW, subReq)
+
+	if subW.Code != http.StatusCreated {
+		t.Fatalf("Create subscription failed: status %d, body: %s", subW.Code, subW.Body.String())
+	}
+
+	var created struct {
+		Subscription webhooks.Subscription `json:"subscription"`
+		Secret       string                `json:"secret"`
+	}
+	if err := json.NewDecoder(subW.Body).Decode(&created); err != nil {
+		t.Fatalf("Failed to decode subscription: %v", err)
+	}
+	secret = created.Secret
+	sub := created.Subscription
+
+	defer func() {
+		delReq := httptest.NewRequest("DELETE", fmt.Sprintf("/webhooks/%s", sub.ID), nil)
+		r.ServeHTTP(httptest.NewRecorder(), delReq)
+	}()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("file", fmt.Sprintf("test_%s.txt", uuid.New().String()))
+	part.Write([]byte("Webhook test document."))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/documents/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Upload failed: status %d, body: %s", w.Code, w.Body.String())
+	}
+
+	var payload webhooks.Payload
+	select {
+	case payload = <-received:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for webhook delivery")
+	}
+
+	if payload.Type != "document.uploaded" {
+		t.Errorf("Expected document.uploaded event, got %s", payload.Type)
+	}
+
+	replayReq := httptest.NewRequest("POST", fmt.Sprintf("/webhooks/%s/deliveries/%s/replay", sub.ID, payload.ID), nil)
+	replayW := httptest.NewRecorder()
+	r.ServeHTTP(replayW, replayReq)
+
+	if replayW.Code != http.StatusAccepted {
+		t.Fatalf("Replay failed: status %d, body: %s", replayW.Code, replayW.Body.String())
+	}
+
+	select {
+	case replayed := <-received:
+		if replayed.ID != payload.ID {
+			t.Errorf("Expected replay to resend payload %s, got %s", payload.ID, replayed.ID)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for replayed delivery")
+	}
+}